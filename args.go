@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 const (
@@ -61,6 +62,18 @@ func EnvPrefix(prefix string) ParseModifier {
 	}
 }
 
+// Every option and config key without an Env() reads an environment variable derived from the
+// env prefix, group and name of the rule. Use NoAutoEnv() to opt a rule out.
+//
+//	parser := args.NewParser(args.EnvPrefix("APP_"), args.AutoEnv())
+//	// Reads 'APP_DATABASE_HOST'
+//	parser.AddConfig("host").InGroup("database")
+func AutoEnv() ParseModifier {
+	return func(parser *ArgParser) {
+		parser.autoEnv = true
+	}
+}
+
 func NoHelp() ParseModifier {
 	return func(parser *ArgParser) {
 		parser.AddHelpOption = false
@@ -79,7 +92,6 @@ func Dedent(input string) string {
 	leadingWhitespace := regexp.MustCompile(`(?m)^[ \t]+`)
 	idx := leadingWhitespace.FindIndex(text)
 	if idx == nil {
-		fmt.Printf("Unable to find \\n::space:: combo\n")
 		return input
 	}
 	//fmt.Printf("idx: '%d:%d'\n", idx[0], idx[1])
@@ -97,42 +109,50 @@ func DedentTrim(input string, cutset string) string {
 	return strings.Trim(Dedent(input), cutset)
 }
 
+// Word wrap the message at the display width given, indenting each new line by the indent
+// given. Width is measured in terminal columns; wide characters count as two columns and
+// ANSI escape sequences are ignored. If the indent leaves less than 20 columns for the
+// message, the message is wrapped at 20 columns instead.
 func WordWrap(msg string, indent int, wordWrap int) string {
 	// Remove any previous formatting
 	regex, _ := regexp.Compile(" {2,}|\n|\t")
 	msg = regex.ReplaceAllString(msg, "")
 
 	wordWrapLen := wordWrap - indent
-	if wordWrapLen <= 0 {
-		panic(fmt.Sprintf("Flag indent spacing '%d' exceeds wordwrap length '%d'\n", indent, wordWrap))
+	if wordWrapLen < minWrapLen {
+		wordWrapLen = minWrapLen
 	}
 
-	if len(msg) < wordWrapLen {
+	if DisplayWidth(msg) < wordWrapLen {
 		return msg
 	}
 
 	// Split the msg into lines
+	cells, widths := splitCells(msg)
 	var lines []string
 	var eol int
-	for i := 0; i < len(msg); {
-		eol = i + wordWrapLen
+	for i := 0; i < len(cells); {
+		// Find the cell where this line reaches the word wrap length
+		width := 0
+		for eol = i; eol < len(cells) && width+widths[eol] <= wordWrapLen; eol++ {
+			width += widths[eol]
+		}
 		// If the End Of Line exceeds the message length + our peek at the next character
-		if (eol + 1) >= len(msg) {
+		if (eol + 1) >= len(cells) {
 			// Slice until the end of the message
-			lines = append(lines, msg[i:len(msg)])
-			i = len(msg)
+			lines = append(lines, strings.Join(cells[i:], ""))
 			break
 		}
-		// Slice this portion of the message into a single line
-		line := msg[i:eol]
 		// If the next character past eol is not a space
 		// (Usually means we are in the middle of a word)
-		if msg[eol+1] != ' ' {
-			// Find the last space before the word wrap
-			idx := strings.LastIndex(line, " ")
-			eol = i + idx
+		if cells[eol+1] != " " {
+			// Find the last space before the word wrap, words longer
+			// than the line are split at the word wrap
+			if idx := lastIndexOf(cells[i:eol], " "); idx > 0 {
+				eol = i + idx
+			}
 		}
-		lines = append(lines, msg[i:eol])
+		lines = append(lines, strings.Join(cells[i:eol], ""))
 		i = eol
 	}
 	var spacer string
@@ -142,11 +162,19 @@ func WordWrap(msg string, indent int, wordWrap int) string {
 		spacer = fmt.Sprintf("\n%%-%ds", indent-1)
 	}
 
-	//fmt.Print("fmt: %s\n", spacer)
 	seperator := fmt.Sprintf(spacer, "")
 	return strings.Join(lines, seperator)
 }
 
+func lastIndexOf(items []string, item string) int {
+	for idx := len(items) - 1; idx >= 0; idx-- {
+		if items[idx] == item {
+			return idx
+		}
+	}
+	return -1
+}
+
 func castString(name string, dest interface{}, value interface{}) (interface{}, error) {
 	// If value is nil, return the type default
 	if value == nil {
@@ -339,6 +367,20 @@ func containsString(needle string, haystack []string) bool {
 	return false
 }
 
+// Returns the text with the first letter of each word in upper case. IE: 'database pool' = 'Database Pool'
+func titleCase(text string) string {
+	var result strings.Builder
+	wordStart := true
+	for _, r := range text {
+		if wordStart {
+			r = unicode.ToTitle(r)
+		}
+		result.WriteRune(r)
+		wordStart = !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}
+	return result.String()
+}
+
 func copyStringSlice(src []string) (dest []string) {
 	dest = make([]string, len(src))
 	for idx, value := range src {
@@ -425,3 +467,76 @@ func (e *HelpError) Error() string {
 func (e *HelpError) IsHelpError() bool {
 	return true
 }
+
+// Returns true if the parser printed shell completion candidates instead of parsing the command line
+func IsCompletion(err error) bool {
+	_, ok := err.(*CompletionError)
+	return ok
+}
+
+type CompletionError struct{}
+
+func (e *CompletionError) Error() string {
+	return "Shell completion was requested; Inspect this error with args.IsCompletion(err)"
+}
+
+// Returns a CommandFunc result which causes RunCommand() to print the error to ErrorIO
+// and return the exit code given
+//
+//	parser.AddCommand("delete", func(subParser *args.ArgParser, data interface{}) (int, error) {
+//		if err := api.Delete(name); err != nil {
+//			return args.Exit(3, err)
+//		}
+//		return 0, nil
+//	})
+func Exit(code int, err error) (int, error) {
+	return code, &ExitError{Code: code, Err: err}
+}
+
+// Returns a CommandFunc result for an error caused by invalid usage of the command. RunCommand()
+// prints the error and the usage of the command to ErrorIO and returns the UsageExitCode of the parser
+func ExitUsage(err error) (int, error) {
+	return 2, &ExitError{Err: err, Usage: true}
+}
+
+// Returns the ExitError if the error or an error it wraps is an ExitError. Errors wrapped
+// with pkg/errors and with fmt.Errorf("%w") are both followed
+//
+//	if exitErr, ok := args.AsExitError(err); ok {
+//		os.Exit(exitErr.Code)
+//	}
+func AsExitError(err error) (*ExitError, bool) {
+	for err != nil {
+		if exitErr, ok := err.(*ExitError); ok {
+			return exitErr, true
+		}
+		switch wrapper := err.(type) {
+		case interface{ Cause() error }:
+			err = wrapper.Cause()
+		case interface{ Unwrap() error }:
+			err = wrapper.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+type ExitError struct {
+	// The exit code, unused if Usage is true since RunCommand() returns the UsageExitCode of the parser
+	Code int
+	Err  error
+	// If true the usage of the command is printed with the error
+	Usage bool
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,190 @@
+// Package argstest runs an args.ArgParser in process, capturing the output and exit
+// code such that command line programs can be tested without building a binary.
+//
+//	parser := newParser()
+//	result := argstest.Run(parser, []string{"volume", "create", "my-vol"},
+//		map[string]string{"API_ENDPOINT": "http://localhost"}, "")
+//	Expect(result.ExitCode).To(Equal(0))
+//	Expect(result.Stdout).To(ContainSubstring("created 'my-vol'"))
+package argstest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/thrawn01/args"
+)
+
+// If this environment variable is set, the golden helpers update the golden
+// files with the actual output instead of comparing. IE: `UPDATE_GOLDEN=1 go test`
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// The outcome of running a parser with Run()
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	// The options resolved by the parser, nil if parsing failed before values were applied
+	Options *args.Options
+	// The error returned by ParseAndRun()
+	Err error
+}
+
+// Guards the process wide os.Stdin, os.Stdout and os.Stderr swapped by Run()
+var mutex sync.Mutex
+
+// Parse the args with the parser and run the chosen command the same way ParseAndRun()
+// would. The environment variables the rules read are taken only from 'env' and 'stdin'
+// is provided to the command as os.Stdin and the InputIO of the parser. Output written to
+// os.Stdout, os.Stderr and the HelpIO and ErrorIO of the parser is captured in the Result.
+// If a command calls ParseOrExit() the exit code is captured instead of exiting the process.
+//
+// Since os.Stdin, os.Stdout and os.Stderr are process wide, calls to Run() are serialized.
+// Rules remember the values they parsed, so create a new parser for each call to Run()
+func Run(parser *args.ArgParser, cmdLine []string, env map[string]string, stdin string) Result {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	stdoutDone := capture(&os.Stdout)
+	stderrDone := capture(&os.Stderr)
+
+	inReader, inWriter, err := os.Pipe()
+	if err != nil {
+		panic(fmt.Sprintf("while creating stdin pipe: %s", err))
+	}
+	go func() {
+		io.WriteString(inWriter, stdin)
+		inWriter.Close()
+	}()
+	defer func(file *os.File) { os.Stdin = file }(os.Stdin)
+	os.Stdin = inReader
+	defer inReader.Close()
+
+	defer func(inputIO io.Reader, helpIO, errorIO io.Writer, exit func(int), lookupEnv func(string) (string, bool)) {
+		parser.InputIO, parser.HelpIO, parser.ErrorIO = inputIO, helpIO, errorIO
+		parser.Exit, parser.LookupEnv = exit, lookupEnv
+	}(parser.InputIO, parser.HelpIO, parser.ErrorIO, parser.Exit, parser.LookupEnv)
+	parser.InputIO, parser.HelpIO, parser.ErrorIO = os.Stdin, os.Stdout, os.Stderr
+	parser.Exit = func(code int) {
+		panic(exitCode(code))
+	}
+	parser.LookupEnv = func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	retCode, err := run(parser, append([]string{}, cmdLine...))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	return Result{
+		Stdout:   stdoutDone(),
+		Stderr:   stderrDone(),
+		ExitCode: retCode,
+		Options:  parser.GetOpts(),
+		Err:      err,
+	}
+}
+
+// Passed to panic() by the Exit function of the parser to stop the command
+type exitCode int
+
+// Run the parser, recovering the exit code if the command called Exit() on the parser
+func run(parser *args.ArgParser, cmdLine []string) (retCode int, err error) {
+	defer func() {
+		value := recover()
+		if code, ok := value.(exitCode); ok {
+			retCode, err = int(code), nil
+		} else if value != nil {
+			panic(value)
+		}
+	}()
+	return parser.ParseAndRun(&cmdLine, nil)
+}
+
+// Replace the file with a pipe, the function returned restores the
+// file and returns everything written to the pipe
+func capture(file **os.File) func() string {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		panic(fmt.Sprintf("while creating capture pipe: %s", err))
+	}
+
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(&buf, reader)
+		close(done)
+	}()
+
+	original := *file
+	*file = writer
+	return func() string {
+		*file = original
+		writer.Close()
+		<-done
+		reader.Close()
+		return buf.String()
+	}
+}
+
+// Compare the actual output with the contents of the golden file, returns an error describing
+// the difference if they do not match. If UPDATE_GOLDEN is set the golden file is written instead.
+//
+//	err := argstest.CompareGolden("testdata/status.golden", result.Stdout)
+//	Expect(err).To(BeNil())
+func CompareGolden(fileName, actual string) error {
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			return errors.Wrapf(err, "while creating directory for golden file '%s'", fileName)
+		}
+		if err := ioutil.WriteFile(fileName, []byte(actual), 0644); err != nil {
+			return errors.Wrapf(err, "while writing golden file '%s'", fileName)
+		}
+		return nil
+	}
+
+	expected, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "while reading golden file '%s'; run with %s=1 to create it",
+			fileName, UpdateGoldenEnv)
+	}
+	if string(expected) == actual {
+		return nil
+	}
+	return errors.Errorf("output does not match golden file '%s'%s", fileName, diff(string(expected), actual))
+}
+
+// Compare the output of GenerateHelp() with the contents of the golden file
+//
+//	err := argstest.CompareHelp(parser, "testdata/help.golden")
+func CompareHelp(parser *args.ArgParser, fileName string) error {
+	return CompareGolden(fileName, parser.GenerateHelp())
+}
+
+// Describe the first line that differs between the expected and actual output
+func diff(expected, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	for idx := 0; idx < len(expectedLines) || idx < len(actualLines); idx++ {
+		var want, got string
+		if idx < len(expectedLines) {
+			want = expectedLines[idx]
+		}
+		if idx < len(actualLines) {
+			got = actualLines[idx]
+		}
+		if want != got || idx >= len(expectedLines) || idx >= len(actualLines) {
+			return fmt.Sprintf(" at line %d\n  expected: %q\n  actual:   %q", idx+1, want, got)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package argstest_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+	"github.com/thrawn01/args/argstest"
+)
+
+func TestArgsTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Args Test Harness")
+}
+
+func newParser() *args.ArgParser {
+	parser := args.NewParser(args.Name("mycli"), args.Desc("Manage volumes"))
+	parser.AddOption("--endpoint").Env("API_ENDPOINT").Default("http://localhost").
+		Help("The api endpoint")
+	parser.AddCommand("echo", func(subParser *args.ArgParser, data interface{}) (int, error) {
+		input, _ := ioutil.ReadAll(os.Stdin)
+		fmt.Printf("%s via %s", input, subParser.GetOpts().String("endpoint"))
+		fmt.Fprint(os.Stderr, "done")
+		return 3, nil
+	}).Help("Echo stdin")
+	return parser
+}
+
+var _ = Describe("argstest", func() {
+	Describe("Run()", func() {
+		It("Should capture output, exit code and options", func() {
+			result := argstest.Run(newParser(), []string{"echo"},
+				map[string]string{"API_ENDPOINT": "http://example.com"}, "hello")
+			Expect(result.Err).To(BeNil())
+			Expect(result.ExitCode).To(Equal(3))
+			Expect(result.Stdout).To(Equal("hello via http://example.com"))
+			Expect(result.Stderr).To(Equal("done"))
+			Expect(result.Options.String("endpoint")).To(Equal("http://example.com"))
+		})
+		It("Should not read the process environment", func() {
+			os.Setenv("API_ENDPOINT", "http://process.com")
+			defer os.Unsetenv("API_ENDPOINT")
+
+			result := argstest.Run(newParser(), []string{"echo"}, nil, "")
+			Expect(result.Options.String("endpoint")).To(Equal("http://localhost"))
+		})
+		It("Should capture the help message", func() {
+			result := argstest.Run(newParser(), []string{"--help"}, nil, "")
+			Expect(result.ExitCode).To(Equal(0))
+			Expect(result.Stdout).To(ContainSubstring("Usage: mycli [OPTIONS]"))
+		})
+		It("Should capture errors in stderr", func() {
+			parser := newParser()
+			parser.AddOption("--count").IsInt()
+			result := argstest.Run(parser, []string{"--count", "many"}, nil, "")
+			Expect(result.Err).ToNot(BeNil())
+			Expect(result.ExitCode).To(Equal(2))
+			Expect(result.Stderr).To(ContainSubstring("Invalid value for '--count'"))
+		})
+	})
+
+	Describe("CompareHelp()", func() {
+		It("Should match the golden file", func() {
+			Expect(argstest.CompareHelp(newParser(), "testdata/help.golden")).To(Succeed())
+		})
+		It("Should describe the line that does not match", func() {
+			dir, _ := ioutil.TempDir("", "argstest")
+			defer os.RemoveAll(dir)
+			fileName := filepath.Join(dir, "help.golden")
+			ioutil.WriteFile(fileName, []byte("Usage: other\n"), 0644)
+
+			err := argstest.CompareHelp(newParser(), fileName)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("at line 1\n  expected: \"Usage: other\""))
+		})
+	})
+})
@@ -0,0 +1,145 @@
+package args
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type ColorMode int
+
+const (
+	// Style the help message and errors if they are written to a terminal, this is the default
+	ColorAuto ColorMode = iota
+	// Never style the help message or errors
+	ColorNever
+	// Always style the help message and errors
+	ColorAlways
+)
+
+// The ANSI SGR parameters used to style each part of the help message and errors,
+// an empty string leaves that part unstyled. IE: '1' for bold or '1;34' for bold blue
+type Style struct {
+	// Section titles. IE: 'Usage:', 'Options:'
+	Heading string
+	// Option flags, commands, config keys and environment variables
+	Flag string
+	// Positional argument names
+	Metavar string
+	// The defaults and environment variables listed after the help message
+	Default string
+	// Error messages
+	Error string
+}
+
+// The style used when Theme() was not given
+var DefaultStyle = Style{
+	Heading: "1",
+	Flag:    "36",
+	Metavar: "33",
+	Default: "2",
+	Error:   "31",
+}
+
+// Style the help message and error messages with ANSI escapes, by default output is styled
+// when written to a terminal. Colour is always disabled if the NO_COLOR environment variable
+// is set or the user passed '--no-color', which is added to the parser unless the mode is ColorNever.
+//
+//	parser := args.NewParser(args.Color(args.ColorNever))
+func Color(mode ColorMode) ParseModifier {
+	return func(parser *ArgParser) {
+		parser.colorMode = mode
+	}
+}
+
+// Replace the DefaultStyle used when colour is enabled
+//
+//	parser := args.NewParser(args.Theme(args.Style{Heading: "1;4", Flag: "32"}))
+func Theme(style Style) ParseModifier {
+	return func(parser *ArgParser) {
+		parser.style = &style
+	}
+}
+
+// Add the --no-color option if the help message could be styled. A parser without
+// rules is left alone so Parse() can report that no options were added
+func (self *ArgParser) addColorOption() {
+	if self.colorMode == ColorNever || self.IsSubParser || len(self.rules) == 0 || self.GetRule("no-color") != nil {
+		return
+	}
+	self.AddOption("--no-color").IsTrue().Persistent().NoAutoEnv().Help("Disable coloured output")
+	self.colorAdded = true
+}
+
+// Returns true if output written to the writer should be styled
+func (self *ArgParser) colorEnabled(out io.Writer) bool {
+	if self.colorMode == ColorNever {
+		return false
+	}
+	lookupEnv := self.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	if value, ok := lookupEnv("NO_COLOR"); ok && value != "" {
+		return false
+	}
+	if opts := self.GetOpts(); self.colorAdded && opts != nil && opts.Bool("no-color") {
+		return false
+	}
+	if self.colorMode == ColorAlways {
+		return true
+	}
+	file, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	_, isTerminal := terminalWidth(file)
+	return isTerminal
+}
+
+// Returns the text wrapped in the ANSI escapes for the SGR parameters given
+// if colour is enabled for the writer
+func (self *ArgParser) colorize(out io.Writer, sgr string, text string) string {
+	if sgr == "" || text == "" || !self.colorEnabled(out) {
+		return text
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m", sgr, text)
+}
+
+// Returns the style used when colour is enabled
+func (self *ArgParser) getStyle() *Style {
+	if self.style == nil {
+		return &DefaultStyle
+	}
+	return self.style
+}
+
+// Returns the section title styled as a heading. IE: 'Options:'
+func (self *ArgParser) heading(title string) string {
+	return self.colorize(self.HelpIO, self.getStyle().Heading, title)
+}
+
+// Returns the flags column of a help message styled as flags or metavars, leading spaces are not styled
+func (self *ArgParser) styleFlags(rule *Rule, flags string) string {
+	sgr := self.getStyle().Flag
+	if rule.HasFlag(IsArgument) {
+		sgr = self.getStyle().Metavar
+	}
+	name := strings.TrimLeft(flags, " ")
+	return flags[:len(flags)-len(name)] + self.colorize(self.HelpIO, sgr, name)
+}
+
+// Returns the help message with the defaults and environment variables of the rule styled
+func (self *ArgParser) styleMessage(rule *Rule, message string) string {
+	parens := rule.helpParens()
+	if parens == "" || !strings.HasSuffix(message, parens) {
+		return message
+	}
+	return strings.TrimSuffix(message, parens) + self.colorize(self.HelpIO, self.getStyle().Default, parens)
+}
+
+// Print the error to ErrorIO styled as an error
+func (self *ArgParser) printError(format string, args ...interface{}) {
+	fmt.Fprintln(self.ErrorIO, self.colorize(self.ErrorIO, self.getStyle().Error, fmt.Sprintf(format, args...)))
+}
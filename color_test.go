@@ -0,0 +1,73 @@
+package args_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+)
+
+var _ = Describe("Color", func() {
+	var stdout, stderr bytes.Buffer
+	var env map[string]string
+	var parser *args.ArgParser
+
+	BeforeEach(func() {
+		stdout.Reset()
+		stderr.Reset()
+		env = map[string]string{}
+		parser = args.NewParser(args.Name("my-cli"), args.NoHelp(), args.WrapLen(80),
+			args.Color(args.ColorAlways), args.Theme(args.Style{Heading: "1", Flag: "36", Default: "2", Error: "31"}))
+		parser.HelpIO = &stdout
+		parser.ErrorIO = &stderr
+		parser.Exit = func(int) {}
+		parser.LookupEnv = func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		}
+		parser.AddOption("--endpoint").Default("localhost").Help("The api endpoint")
+	})
+
+	Describe("args.Color()", func() {
+		It("Should style headings, flags and defaults in the help message", func() {
+			_, err := parser.Parse(&[]string{})
+			Expect(err).To(BeNil())
+			Expect(parser.GenerateHelp()).To(Equal("\x1b[1mUsage:\x1b[0m my-cli [OPTIONS] \n" +
+				"\n\x1b[1mOptions:\x1b[0m\n" +
+				"  \x1b[36m--endpoint\x1b[0m   The api endpoint \x1b[2m(Default=localhost)\x1b[0m\n" +
+				"  \x1b[36m--no-color\x1b[0m   Disable coloured output\n"))
+		})
+		It("Should style errors", func() {
+			parser.ParseOrExit(&[]string{"--endpoint"})
+			Expect(stderr.String()).To(Equal("\x1b[31mExpected '--endpoint' to have an argument\x1b[0m\n"))
+		})
+		It("Should not style output if --no-color is given", func() {
+			_, err := parser.Parse(&[]string{"--no-color"})
+			Expect(err).To(BeNil())
+			Expect(parser.GenerateHelp()).To(HavePrefix("Usage: my-cli [OPTIONS] \n\nOptions:\n  --endpoint   "))
+		})
+		It("Should not style output if NO_COLOR is set", func() {
+			env["NO_COLOR"] = "1"
+			_, err := parser.Parse(&[]string{})
+			Expect(err).To(BeNil())
+			Expect(parser.GenerateHelp()).To(HavePrefix("Usage: my-cli [OPTIONS] \n\nOptions:\n  --endpoint   "))
+		})
+		It("Should default to ColorAuto and not style output that is not written to a terminal", func() {
+			parser := args.NewParser(args.Name("my-cli"))
+			parser.HelpIO = &stdout
+			parser.AddOption("--endpoint").Help("The api endpoint")
+			_, err := parser.Parse(&[]string{})
+			Expect(err).To(BeNil())
+			Expect(parser.GenerateHelp()).ToNot(ContainSubstring("\x1b["))
+			Expect(parser.GenerateHelp()).To(ContainSubstring("--no-color"))
+		})
+		It("Should not add --no-color with ColorNever", func() {
+			parser := args.NewParser(args.Name("my-cli"), args.Color(args.ColorNever))
+			parser.AddOption("--endpoint").Help("The api endpoint")
+			_, err := parser.Parse(&[]string{})
+			Expect(err).To(BeNil())
+			Expect(parser.GetRule("no-color")).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,146 @@
+package args
+
+// Hook called before or after a command is run. Returning an error from a PreRun hook
+// prevents the command from running.
+type HookFunc func(*ArgParser, interface{}) error
+
+// A declarative definition of a command and its sub commands. Unlike AddCommand() the
+// command tree is known before the command line is parsed.
+//
+//	parser.AddCommands(args.Command{
+//		Name: "volume",
+//		Help: "Manage volumes",
+//		PreRun: func(subParser *args.ArgParser, data interface{}) error {
+//			return authenticate(subParser.GetOpts())
+//		},
+//		Subcommands: []args.Command{
+//			{
+//				Name: "create",
+//				Help: "Create a new volume",
+//				Options: func(subParser *args.ArgParser) {
+//					subParser.AddArgument("name").Required().Help("The name of the volume")
+//				},
+//				Run: createVolume,
+//			},
+//		},
+//	})
+type Command struct {
+	// The name of the command as typed on the command line
+	Name string
+	// Alternative names the command can go by
+	Aliases []string
+	// The help message displayed in the 'Commands' section of the parent
+	Help string
+	// Hide the command from the help message
+	Hidden bool
+	// List the command under a titled section of the same category in the help message
+	Category string
+	// Called with the sub parser to add options and arguments for this command
+	Options func(*ArgParser)
+	// Run when this command is chosen, or when none of its Subcommands are chosen
+	Run CommandFunc
+	// The name of the sub command run when none of the Subcommands are chosen and Run is nil
+	Default string
+	// Run before this command or any of its descendant commands
+	PreRun HookFunc
+	// Run after this command or any of its descendant commands
+	PostRun HookFunc
+	// Commands nested under this command
+	Subcommands []Command
+	// Listed in the 'Examples:' section of the help message of this command
+	Examples []Example
+}
+
+// Add commands from a declarative command tree
+func (self *ArgParser) AddCommands(commands ...Command) {
+	for idx := range commands {
+		self.addCommandDef(&commands[idx])
+	}
+}
+
+func (self *ArgParser) addCommandDef(cmd *Command) *RuleModifier {
+	modifier := self.AddCommand(cmd.Name, func(subParser *ArgParser, data interface{}) (int, error) {
+		return subParser.runCommandDef(cmd, data)
+	}).Help(cmd.Help)
+
+	for _, alias := range cmd.Aliases {
+		modifier.Alias(alias)
+	}
+	if cmd.Hidden {
+		modifier.Hidden()
+	}
+	modifier.InCategory(cmd.Category)
+	modifier.GetRule().CommandDef = cmd
+	modifier.GetRule().Examples = cmd.Examples
+	return modifier
+}
+
+// Add the options, sub commands and hooks of the command definition to this parser
+func (self *ArgParser) applyCommandDef(cmd *Command) {
+	// The help of the command describes the sub parser
+	if cmd.Help != "" {
+		self.Description = cmd.Help
+	}
+	if cmd.Options != nil {
+		cmd.Options(self)
+	}
+	self.AddCommands(cmd.Subcommands...)
+	if cmd.Default != "" {
+		self.DefaultCommand(cmd.Default)
+	}
+
+	if cmd.PreRun != nil || cmd.PostRun != nil {
+		self.hooks = append(self.hooks, commandHooks{preRun: cmd.PreRun, postRun: cmd.PostRun})
+	}
+}
+
+func (self *ArgParser) runCommandDef(cmd *Command, data interface{}) (int, error) {
+	self.applyCommandDef(cmd)
+
+	_, err := self.Parse(nil)
+	if err != nil {
+		if IsHelpError(err) {
+			self.PrintHelp()
+			return self.HelpExitCode, nil
+		}
+		return self.UsageExitCode, err
+	}
+
+	// If one of our sub commands was chosen
+	if self.Command != nil || cmd.Run == nil {
+		return self.RunCommand(data)
+	}
+	return self.runWithHooks(cmd.Run, data)
+}
+
+// Run the command along with the PreRun and PostRun hooks of the command and all its parents
+func (self *ArgParser) runWithHooks(run CommandFunc, data interface{}) (retCode int, err error) {
+	hooks := self.hooks
+	// Sub parsers created by the command should not run our hooks again
+	self.hooks = nil
+
+	for _, level := range hooks {
+		level := level
+		if level.preRun != nil {
+			if err := level.preRun(self, data); err != nil {
+				return 1, err
+			}
+		}
+		// The PostRun of every command whose PreRun succeeded runs, even if a sub command's
+		// PreRun fails. Deferred hooks run in reverse order such that the parent cleans up last
+		if level.postRun != nil {
+			defer func() {
+				if hookErr := level.postRun(self, data); hookErr != nil && err == nil {
+					retCode, err = 1, hookErr
+				}
+			}()
+		}
+	}
+	return run(self, data)
+}
+
+// The PreRun and PostRun hooks of a single command in the command tree
+type commandHooks struct {
+	preRun  HookFunc
+	postRun HookFunc
+}
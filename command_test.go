@@ -0,0 +1,152 @@
+package args_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+)
+
+var _ = Describe("Command", func() {
+	var calls []string
+	var parser *args.ArgParser
+
+	record := func(name string) args.HookFunc {
+		return func(subParser *args.ArgParser, data interface{}) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	BeforeEach(func() {
+		calls = nil
+		parser = args.NewParser(args.Name("my-cli"))
+		parser.AddCommands(args.Command{
+			Name:    "volume",
+			Help:    "Manage volumes",
+			PreRun:  record("volume-pre"),
+			PostRun: record("volume-post"),
+			Subcommands: []args.Command{
+				{
+					Name:    "create",
+					Aliases: []string{"new"},
+					Help:    "Create a volume",
+					PreRun:  record("create-pre"),
+					PostRun: record("create-post"),
+					Options: func(subParser *args.ArgParser) {
+						subParser.AddArgument("name").Required()
+					},
+					Run: func(subParser *args.ArgParser, data interface{}) (int, error) {
+						calls = append(calls, "create:"+subParser.GetOpts().String("name"))
+						return 0, nil
+					},
+				},
+			},
+		})
+	})
+
+	Describe("ArgParser.AddCommands()", func() {
+		It("Should run nested commands", func() {
+			cmdLine := []string{"volume", "new", "my-volume"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(0))
+			Expect(calls).To(Equal([]string{"volume-pre", "create-pre", "create:my-volume",
+				"create-post", "volume-post"}))
+		})
+		It("Should run the Default sub command if none was given", func() {
+			parser := args.NewParser(args.Name("my-cli"))
+			parser.AddCommands(args.Command{
+				Name:    "volume",
+				Default: "list",
+				Subcommands: []args.Command{
+					{
+						Name: "list",
+						Options: func(subParser *args.ArgParser) {
+							subParser.AddOption("--all").IsTrue()
+						},
+						Run: func(subParser *args.ArgParser, data interface{}) (int, error) {
+							calls = append(calls, fmt.Sprintf("list:%t", subParser.GetOpts().Bool("all")))
+							return 0, nil
+						},
+					},
+				},
+			})
+			cmdLine := []string{"volume", "--all"}
+			_, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(calls).To(Equal([]string{"list:true"}))
+		})
+		It("Should register the command tree before parsing", func() {
+			rule := parser.GetRule("!cmd-volume")
+			Expect(rule).ToNot(BeNil())
+			Expect(rule.CommandDef.Subcommands[0].Name).To(Equal("create"))
+		})
+		It("Should not run the command if a PreRun hook fails", func() {
+			parser := args.NewParser()
+			parser.AddCommands(args.Command{
+				Name: "show",
+				PreRun: func(subParser *args.ArgParser, data interface{}) error {
+					return errors.New("not authorized")
+				},
+				Run: func(subParser *args.ArgParser, data interface{}) (int, error) {
+					calls = append(calls, "show")
+					return 0, nil
+				},
+			})
+			cmdLine := []string{"show"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("not authorized"))
+			Expect(retCode).To(Equal(1))
+			Expect(calls).To(BeEmpty())
+		})
+		It("Should run the PostRun of parents whose PreRun succeeded if a child PreRun fails", func() {
+			parser := args.NewParser()
+			parser.AddCommands(args.Command{
+				Name:    "volume",
+				PreRun:  record("volume-pre"),
+				PostRun: record("volume-post"),
+				Subcommands: []args.Command{
+					{
+						Name: "create",
+						PreRun: func(subParser *args.ArgParser, data interface{}) error {
+							calls = append(calls, "create-pre")
+							return errors.New("not authorized")
+						},
+						PostRun: record("create-post"),
+						Run: func(subParser *args.ArgParser, data interface{}) (int, error) {
+							calls = append(calls, "create")
+							return 0, nil
+						},
+					},
+				},
+			})
+			cmdLine := []string{"volume", "create"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(MatchError("not authorized"))
+			Expect(retCode).To(Equal(1))
+			Expect(calls).To(Equal([]string{"volume-pre", "create-pre", "volume-post"}))
+		})
+		It("Should run hooks for commands added with AddCommand()", func() {
+			parser := args.NewParser()
+			parser.AddCommands(args.Command{
+				Name:   "volume",
+				PreRun: record("volume-pre"),
+				Options: func(subParser *args.ArgParser) {
+					subParser.AddCommand("list", func(subParser *args.ArgParser, data interface{}) (int, error) {
+						calls = append(calls, "list")
+						return 0, nil
+					})
+				},
+			})
+			cmdLine := []string{"volume", "list"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(0))
+			Expect(calls).To(Equal([]string{"volume-pre", "list"}))
+		})
+	})
+})
@@ -0,0 +1,254 @@
+package args
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// The hidden command the generated completion scripts use to ask the program for
+// completion candidates. IE: `my-cli __complete volume cre`
+const CompleteCommand = "__complete"
+
+// Candidate printed by the __complete command when the shell should complete file paths
+const CompleteFiles = ":files"
+
+var regexNonWord = regexp.MustCompile(`\W`)
+
+// Returns completion candidates for the word currently being completed
+type CompleterFunc func(prefix string) []string
+
+// Generate a shell completion script for bash, zsh or fish. The script asks the program
+// for completion candidates via the hidden __complete command, which includes options,
+// commands and sub commands defined with AddCommands(), Choices() and candidates
+// returned by Completer() at run time
+//
+//	# Load completion for the current bash session
+//	source <(my-cli completion bash)
+func (self *ArgParser) GenerateCompletion(shell string, w io.Writer) error {
+	script, ok := completionScripts[shell]
+	if !ok {
+		return errors.Errorf("unsupported shell '%s'; choose from (bash, zsh, fish)", shell)
+	}
+
+	name := self.Name
+	if name == "" {
+		name = path.Base(os.Args[0])
+	}
+	return script.Execute(w, struct {
+		Name     string
+		FuncName string
+		Complete string
+		Files    string
+	}{
+		Name:     name,
+		FuncName: regexNonWord.ReplaceAllString(name, "_"),
+		Complete: CompleteCommand,
+		Files:    CompleteFiles,
+	})
+}
+
+// Adds a 'completion' command such that `my-cli completion bash` prints the bash
+// completion script for this parser
+func (self *ArgParser) AddCompletionCommand() *RuleModifier {
+	return self.AddCommand("completion", func(subParser *ArgParser, data interface{}) (int, error) {
+		subParser.AddArgument("shell").Required().Choices([]string{"bash", "zsh", "fish"}).
+			Help("The shell to generate the completion script for")
+		opts, err := subParser.Parse(nil)
+		if err != nil {
+			if IsHelpError(err) {
+				subParser.PrintHelp()
+				return subParser.HelpExitCode, nil
+			}
+			return subParser.UsageExitCode, err
+		}
+		if err := self.GenerateCompletion(opts.String("shell"), self.HelpIO); err != nil {
+			return 1, err
+		}
+		return 0, nil
+	}).Help("Generate a shell completion script")
+}
+
+// Write the completion candidates for the words given to HelpIO, one per line.
+// The last word is the partial word being completed
+func (self *ArgParser) complete(words []string) {
+	for _, candidate := range self.Complete(words) {
+		fmt.Fprintln(self.HelpIO, candidate)
+	}
+}
+
+// Returns the completion candidates for the last word given. Sub commands are only
+// completed if they were defined with AddCommands().
+//
+//	// Returns []string{"create", "delete"}
+//	candidates := parser.Complete([]string{"volume", ""})
+func (self *ArgParser) Complete(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := words[len(words)-1]
+
+	parser := self
+	var positional int
+	for idx := 0; idx < len(words)-1; idx++ {
+		word := words[idx]
+
+		rule := parser.matchAlias(word, IsCommand|IsOption)
+		if rule == nil {
+			positional++
+			continue
+		}
+
+		if rule.HasFlag(IsCommand) {
+			// We can only complete commands whose options are known before they run
+			sub := parser.commandDefParser(rule)
+			if sub == nil {
+				return nil
+			}
+			parser, positional = sub, 0
+			continue
+		}
+
+		// If this option expects a value
+		if rule.Action == nil {
+			if idx+1 == len(words)-1 {
+				return rule.completeValue(current)
+			}
+			idx++
+		}
+	}
+
+	var candidates []string
+	// Only complete options if the user started typing an option prefix
+	if current != "" && regexNonWord.MatchString(current[:1]) {
+		for _, rule := range parser.helpRules(IsOption) {
+			candidates = append(candidates, filterPrefix(current, rule.Aliases)...)
+		}
+		for _, rule := range parser.globalRules() {
+			candidates = append(candidates, filterPrefix(current, rule.Aliases)...)
+		}
+		return candidates
+	}
+
+	for _, rule := range parser.helpRules(IsCommand) {
+		candidates = append(candidates, filterPrefix(current, rule.Aliases)...)
+	}
+
+	// Complete the value of the next positional argument
+	arguments := parser.helpRules(IsArgument)
+	for idx, rule := range arguments {
+		if idx == positional || (idx == len(arguments)-1 && rule.HasFlag(IsGreedy) && idx < positional) {
+			candidates = append(candidates, rule.completeValue(current)...)
+			break
+		}
+	}
+	return candidates
+}
+
+// Returns the first rule with the flags given that has an alias matching the word
+func (self *ArgParser) matchAlias(word string, flags int64) *Rule {
+	for _, rule := range self.rules {
+		if rule.HasFlag(flags) && containsString(word, rule.Aliases) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// Returns a sub parser with the options and sub commands of the command definition
+// applied. Returns nil if the command was added via AddCommand() since its options
+// are not known until the command runs.
+func (self *ArgParser) commandDefParser(rule *Rule) *ArgParser {
+	if rule.CommandDef == nil {
+		return nil
+	}
+	parser := self.SubParser()
+	parser.Name = self.commandPath(rule)
+	parser.examples = rule.Examples
+	parser.applyCommandDef(rule.CommandDef)
+	return parser
+}
+
+// Returns the completion candidates for the value of this rule
+func (self *Rule) completeValue(prefix string) []string {
+	candidates := filterPrefix(prefix, self.Choices)
+	if self.Completer != nil {
+		candidates = append(candidates, filterPrefix(prefix, self.Completer(prefix))...)
+	}
+	if self.HasFlag(IsFilePath) {
+		candidates = append(candidates, CompleteFiles)
+	}
+	return candidates
+}
+
+func filterPrefix(prefix string, items []string) []string {
+	var results []string
+	for _, item := range items {
+		if strings.HasPrefix(item, prefix) {
+			results = append(results, item)
+		}
+	}
+	return results
+}
+
+var completionScripts = map[string]*template.Template{
+	"bash": template.Must(template.New("bash").Parse(`# bash completion for {{.Name}}
+_{{.FuncName}}_complete() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    local -a candidates
+    candidates=($("{{.Name}}" {{.Complete}} "${COMP_WORDS[@]:1:$COMP_CWORD}" 2>/dev/null))
+
+    COMPREPLY=()
+    local candidate
+    for candidate in "${candidates[@]}"; do
+        if [[ "$candidate" == "{{.Files}}" ]]; then
+            COMPREPLY+=($(compgen -f -- "$cur"))
+        else
+            COMPREPLY+=("$candidate")
+        fi
+    done
+}
+complete -F _{{.FuncName}}_complete {{.Name}}
+`)),
+	"zsh": template.Must(template.New("zsh").Parse(`#compdef {{.Name}}
+
+_{{.FuncName}}_complete() {
+    local -a candidates
+    local candidate
+    candidates=("${(@f)$("{{.Name}}" {{.Complete}} "${(@)words[2,$CURRENT]}" 2>/dev/null)}")
+
+    for candidate in $candidates; do
+        if [[ "$candidate" == "{{.Files}}" ]]; then
+            _files
+        elif [[ -n "$candidate" ]]; then
+            compadd -- "$candidate"
+        fi
+    done
+}
+
+compdef _{{.FuncName}}_complete {{.Name}}
+`)),
+	"fish": template.Must(template.New("fish").Parse(`# fish completion for {{.Name}}
+function __{{.FuncName}}_complete
+    set -l words (commandline -opc)
+    set -e words[1]
+    set -l current (commandline -ct)
+    for candidate in ("{{.Name}}" {{.Complete}} $words "$current" 2>/dev/null)
+        if test "$candidate" = "{{.Files}}"
+            __fish_complete_path "$current"
+        else
+            echo $candidate
+        end
+    end
+end
+
+complete -c {{.Name}} -f -a '(__{{.FuncName}}_complete)'
+`)),
+}
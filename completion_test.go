@@ -0,0 +1,96 @@
+package args_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+)
+
+var _ = Describe("Completion", func() {
+	var parser *args.ArgParser
+
+	BeforeEach(func() {
+		parser = args.NewParser(args.Name("my-cli"))
+		parser.AddOption("--verbose").Alias("-v").Count().Persistent()
+		parser.AddOption("--endpoint").Alias("-e")
+		parser.AddOption("--format").Choices([]string{"json", "yaml", "text"})
+		parser.AddCommand("debug", nil).Hidden()
+		parser.AddCommands(args.Command{
+			Name: "volume",
+			Subcommands: []args.Command{
+				{
+					Name: "create",
+					Options: func(subParser *args.ArgParser) {
+						subParser.AddOption("--config").IsFilePath()
+						subParser.AddArgument("name").Completer(func(prefix string) []string {
+							return []string{"vol-one", "vol-two", "other"}
+						})
+					},
+				},
+				{Name: "delete", Aliases: []string{"rm"}},
+			},
+		})
+	})
+
+	Describe("ArgParser.Complete()", func() {
+		It("Should complete command names", func() {
+			Expect(parser.Complete([]string{""})).To(Equal([]string{"volume"}))
+			Expect(parser.Complete([]string{"volume", ""})).To(Equal([]string{"create", "delete", "rm"}))
+			Expect(parser.Complete([]string{"-v", "volume", "d"})).To(Equal([]string{"delete"}))
+		})
+		It("Should complete options", func() {
+			Expect(parser.Complete([]string{"--e"})).To(Equal([]string{"--endpoint"}))
+			Expect(parser.Complete([]string{"volume", "create", "--"})).To(
+				Equal([]string{"--config", "--verbose"}))
+		})
+		It("Should complete choices and files for option values", func() {
+			Expect(parser.Complete([]string{"--format", "j"})).To(Equal([]string{"json"}))
+			Expect(parser.Complete([]string{"volume", "create", "--config", ""})).To(
+				Equal([]string{args.CompleteFiles}))
+		})
+		It("Should complete arguments with the Completer", func() {
+			Expect(parser.Complete([]string{"volume", "create", "vol"})).To(
+				Equal([]string{"vol-one", "vol-two"}))
+		})
+		It("Should skip option values when completing", func() {
+			Expect(parser.Complete([]string{"--endpoint", "volume", ""})).To(Equal([]string{"volume"}))
+		})
+	})
+
+	Describe("ArgParser.Parse()", func() {
+		It("Should print candidates for the hidden __complete command", func() {
+			ioReader, ioWriter, _ := os.Pipe()
+			parser.HelpIO = ioWriter
+
+			cmdLine := []string{args.CompleteCommand, "volume", "c"}
+			opts, err := parser.Parse(&cmdLine)
+			Expect(opts).To(BeNil())
+			Expect(args.IsCompletion(err)).To(Equal(true))
+
+			ioWriter.Close()
+			output, _ := ioutil.ReadAll(ioReader)
+			Expect(string(output)).To(Equal("create\n"))
+		})
+	})
+
+	Describe("ArgParser.GenerateCompletion()", func() {
+		It("Should generate completion scripts", func() {
+			for _, shell := range []string{"bash", "zsh", "fish"} {
+				var buf bytes.Buffer
+				Expect(parser.GenerateCompletion(shell, &buf)).To(Succeed())
+				Expect(buf.String()).To(ContainSubstring(`"my-cli" __complete`))
+				Expect(buf.String()).To(ContainSubstring("_my_cli_complete"))
+			}
+		})
+		It("Should return an error for unknown shells", func() {
+			var buf bytes.Buffer
+			err := parser.GenerateCompletion("tcsh", &buf)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("unsupported shell 'tcsh'; choose from (bash, zsh, fish)"))
+		})
+	})
+})
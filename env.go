@@ -0,0 +1,112 @@
+package args
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var envQuoteChars = " \t\n#\"'$\\`"
+
+// Generate the 'Environment:' section of the help message which lists every environment
+// variable read by our rules along with the option it sets, the type and the default
+func (self *ArgParser) GenerateEnvHelp() string {
+	var envs, messages []string
+	indent := 0
+	for _, rule := range self.envRules() {
+		var parens []string
+		parens = append(parens, fmt.Sprintf("Type=%s", rule.typeName()))
+		if rule.Default != nil {
+			parens = append(parens, fmt.Sprintf("Default=%s", *rule.Default))
+		}
+		for _, env := range rule.envVars() {
+			envs = append(envs, env)
+			messages = append(messages, fmt.Sprintf("%s (%s)", rule.targetName(), strings.Join(parens, ", ")))
+			if DisplayWidth(env)+3 > indent {
+				indent = DisplayWidth(env) + 3
+			}
+		}
+	}
+	if len(envs) == 0 {
+		return ""
+	}
+
+	var result bytes.Buffer
+	result.WriteString(self.heading("Environment:") + "\n")
+	for idx := range envs {
+		padding := strings.Repeat(" ", indent-DisplayWidth(envs[idx]))
+		result.WriteString("  " + self.colorize(self.HelpIO, self.getStyle().Flag, envs[idx]) + padding + messages[idx] + "\n")
+	}
+	return result.String()
+}
+
+// Generate a .env file with every environment variable read by our rules assigned their
+// default value. This is printed by --help-env
+//
+//	# The api endpoint
+//	# --endpoint (Type=string)
+//	API_ENDPOINT=http://localhost
+func (self *ArgParser) GenerateEnvFile() string {
+	var result bytes.Buffer
+	for idx, rule := range self.envRules() {
+		if idx != 0 {
+			result.WriteString("\n")
+		}
+		if rule.RuleDesc != "" {
+			result.WriteString("# " + strings.Replace(rule.RuleDesc, "\n", "\n# ", -1) + "\n")
+		}
+		result.WriteString(fmt.Sprintf("# %s (Type=%s)\n", rule.targetName(), rule.typeName()))
+		for _, env := range rule.envVars() {
+			result.WriteString(fmt.Sprintf("%s=%s\n", env, envQuote(rule.defaultString())))
+		}
+	}
+	return result.String()
+}
+
+// Returns the visible rules that read environment variables, rules inherited
+// from parent parsers are included since they are still read by this parser
+func (self *ArgParser) envRules() Rules {
+	var results Rules
+	for _, rule := range append(self.helpRules(IsOption|IsArgument|IsConfig), self.globalRules()...) {
+		if len(rule.envVars()) != 0 {
+			results = append(results, rule)
+		}
+	}
+	return results
+}
+
+// Quote the value if a .env parser would otherwise misinterpret it
+func envQuote(value string) string {
+	if strings.ContainsAny(value, envQuoteChars) {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// Returns the name of the type the rule casts values to, rules with a custom Cast are strings
+func (self *Rule) typeName() string {
+	if self.castType == "" {
+		return "string"
+	}
+	return self.castType
+}
+
+// Returns the name the user knows the rule by. IE: '--endpoint', '<name>' or 'database.user'
+func (self *Rule) targetName() string {
+	switch {
+	case self.HasFlag(IsArgument):
+		return fmt.Sprintf("<%s>", self.Name)
+	case self.HasFlag(IsConfig):
+		if self.Group != DefaultOptionGroup {
+			return self.Group + "." + self.Name
+		}
+		return self.Name
+	}
+	for _, alias := range self.Aliases {
+		if strings.HasPrefix(alias, "--") {
+			return alias
+		}
+	}
+	return self.Aliases[0]
+}
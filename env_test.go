@@ -0,0 +1,117 @@
+package args_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+)
+
+var _ = Describe("Environment", func() {
+	var parser *args.ArgParser
+
+	BeforeEach(func() {
+		parser = args.NewParser(args.Name("my-cli"), args.EnvPrefix("APP_"))
+		parser.AddOption("--endpoint").Alias("-e").Env("ENDPOINT").Default("http://localhost").
+			Help("The api endpoint")
+		parser.AddOption("--retries").IsInt().Env("RETRIES").Default("3")
+		parser.AddOption("--greeting").Env("GREETING").Default("hello world")
+		parser.AddArgument("name").Env("NAME")
+		parser.AddConfig("user").InGroup("database").Env("DB_USER").IsStringSlice().Help("Database users")
+	})
+
+	Describe("ArgParser.GenerateEnvHelp()", func() {
+		It("Should list every environment variable", func() {
+			Expect(parser.GenerateEnvHelp()).To(Equal("Environment:\n" +
+				"  APP_ENDPOINT   --endpoint (Type=string, Default=http://localhost)\n" +
+				"  APP_RETRIES    --retries (Type=int, Default=3)\n" +
+				"  APP_GREETING   --greeting (Type=string, Default=hello world)\n" +
+				"  APP_NAME       <name> (Type=string)\n" +
+				"  APP_DB_USER    database.user (Type=list)\n"))
+		})
+		It("Should align environment variables with wide characters", func() {
+			parser = args.NewParser()
+			parser.AddOption("--name").Env("名前")
+			parser.AddOption("--count").IsInt().Env("COUNT")
+			Expect(parser.GenerateEnvHelp()).To(Equal("Environment:\n" +
+				"  名前    --name (Type=string)\n" +
+				"  COUNT   --count (Type=int)\n"))
+		})
+	})
+
+	Describe("ArgParser.GenerateEnvFile()", func() {
+		It("Should print the environment in .env format with --help-env", func() {
+			var stdout bytes.Buffer
+			parser.HelpIO = &stdout
+
+			cmdLine := []string{"--help-env"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(0))
+			Expect(stdout.String()).To(Equal("# The api endpoint\n" +
+				"# --endpoint (Type=string)\n" +
+				"APP_ENDPOINT=http://localhost\n" +
+				"\n" +
+				"# --retries (Type=int)\n" +
+				"APP_RETRIES=3\n" +
+				"\n" +
+				"# --greeting (Type=string)\n" +
+				"APP_GREETING=\"hello world\"\n" +
+				"\n" +
+				"# Database users\n" +
+				"# database.user (Type=list)\n" +
+				"APP_DB_USER=\n" +
+				"\n" +
+				"# <name> (Type=string)\n" +
+				"APP_NAME=\n"))
+		})
+	})
+
+	Describe("args.AutoEnv()", func() {
+		var env map[string]string
+
+		BeforeEach(func() {
+			env = map[string]string{}
+			parser = args.NewParser(args.Name("my-cli"), args.EnvPrefix("APP_"), args.AutoEnv())
+			parser.LookupEnv = func(key string) (string, bool) {
+				value, ok := env[key]
+				return value, ok
+			}
+			parser.AddOption("--power-level").IsInt()
+			parser.AddOption("--endpoint").Env("API_ENDPOINT")
+			parser.AddOption("--greeting").Default("hello")
+			parser.AddOption("--secret").NoAutoEnv()
+			parser.AddConfig("user").InGroup("database")
+		})
+
+		It("Should read environment variables derived from the rule names", func() {
+			env["APP_POWER_LEVEL"] = "9000"
+			env["APP_API_ENDPOINT"] = "http://localhost"
+			env["APP_DATABASE_USER"] = "root"
+			env["APP_SECRET"] = "shh"
+			env["APP_HELP"] = "true"
+
+			opts, err := parser.Parse(&[]string{})
+			Expect(err).To(BeNil())
+			Expect(opts.Int("power-level")).To(Equal(9000))
+			Expect(opts.String("endpoint")).To(Equal("http://localhost"))
+			Expect(opts.Group("database").String("user")).To(Equal("root"))
+			Expect(opts.String("secret")).To(Equal(""))
+			Expect(opts.Bool("help")).To(Equal(false))
+			Expect(parser.GenerateEnvHelp()).To(ContainSubstring("  APP_DATABASE_USER   database.user (Type=string)\n"))
+		})
+		It("Should override a default with an empty variable", func() {
+			env["APP_GREETING"] = ""
+			opts, err := parser.Parse(&[]string{})
+			Expect(err).To(BeNil())
+			Expect(opts.String("greeting")).To(Equal(""))
+		})
+		It("Should return an error if two rules read the same variable", func() {
+			parser.AddOption("--database-user")
+			_, err := parser.Parse(&[]string{})
+			Expect(err).To(MatchError("Duplicate environment variable 'APP_DATABASE_USER' read by " +
+				"'user' and 'database-user'; use Env() or NoAutoEnv() to resolve"))
+		})
+	})
+})
@@ -0,0 +1,136 @@
+package args
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// An example of how to use the program or command. CmdLine is the command line
+// following the program name and command path, IE: '--size 10 my-volume'
+type Example struct {
+	CmdLine string
+	Desc    string
+}
+
+// Add examples to the 'Examples:' section of the help message
+//
+//	parser := args.NewParser(args.Name("my-cli"), args.Examples(
+//		args.Example{CmdLine: "--endpoint http://localhost volume list", Desc: "List local volumes"},
+//	))
+func Examples(examples ...Example) ParseModifier {
+	return func(parser *ArgParser) {
+		parser.examples = append(parser.examples, examples...)
+	}
+}
+
+// Generate the 'Examples:' section of the help message
+func (self *ArgParser) GenerateExamplesHelp() string {
+	if len(self.examples) == 0 {
+		return ""
+	}
+
+	var result bytes.Buffer
+	result.WriteString(self.heading("Examples:") + "\n")
+	for idx, example := range self.examples {
+		if idx != 0 {
+			result.WriteString("\n")
+		}
+		if example.Desc != "" {
+			result.WriteString("  # " + WordWrap(example.Desc, 4, self.wrapLen()) + "\n")
+		}
+		result.WriteString("  " + self.exampleCmdLine(example) + "\n")
+	}
+	return result.String()
+}
+
+// Returns the command line of the example including the program name and command path
+func (self *ArgParser) exampleCmdLine(example Example) string {
+	return strings.TrimSpace(self.Name + " " + example.CmdLine)
+}
+
+// Parse the command line of each example against the rules of the parser, returns an error
+// for the first example that fails to parse or has arguments no rule matched. Examples of
+// commands defined with AddCommands() are also validated. The options of commands added via
+// AddCommand() are not known until the command runs, so their examples are only checked
+// against the options the command inherits and unmatched arguments are allowed. Call this
+// from a test to catch examples that have gone stale.
+//
+//	It("Should have valid examples", func() {
+//		Expect(newParser().ValidateExamples()).To(Succeed())
+//	})
+func (self *ArgParser) ValidateExamples() error {
+	for _, example := range self.examples {
+		if err := self.validateExample(example); err != nil {
+			return errors.Wrapf(err, "while parsing example '%s'", self.exampleCmdLine(example))
+		}
+	}
+
+	for _, rule := range self.helpRules(IsCommand) {
+		if parser := self.commandDefParser(rule); parser != nil {
+			if err := parser.ValidateExamples(); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, example := range rule.Examples {
+			example.CmdLine = rule.Aliases[0] + " " + example.CmdLine
+			if err := self.validateExample(example); err != nil {
+				return errors.Wrapf(err, "while parsing example '%s'", self.exampleCmdLine(example))
+			}
+		}
+	}
+	return nil
+}
+
+func (self *ArgParser) validateExample(example Example) error {
+	words, err := splitLine(example.CmdLine)
+	if err != nil {
+		return err
+	}
+
+	// Restore the parser and our rules to their current state after parsing the example
+	initial := self.saveRules()
+	defer func(command *Rule, args []string, options *Options, err error) {
+		initial.restore()
+		self.Command, self.args, self.err = command, args, err
+		self.SetOpts(options)
+	}(self.Command, self.args, self.GetOpts(), self.err)
+	self.Command, self.err = nil, nil
+
+	// Follow the example through any commands defined with AddCommands()
+	parser, cmdLine := self, &words
+	for {
+		_, err := parser.Parse(cmdLine)
+		if IsHelpError(err) || IsVersionError(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if parser.Command == nil {
+			return parser.unmatchedArgs()
+		}
+		if parser = parser.commandDefParser(parser.Command); parser == nil {
+			return nil
+		}
+		cmdLine = nil
+	}
+}
+
+// Returns an error if arguments before the terminator were not matched by any rule,
+// which happens when an example uses an option that was misspelled or removed
+func (self *ArgParser) unmatchedArgs() error {
+	var unmatched []string
+	for _, arg := range self.GetArgs() {
+		if arg == DefaultTerminator {
+			break
+		}
+		unmatched = append(unmatched, arg)
+	}
+	if len(unmatched) != 0 {
+		return errors.Errorf("unrecognized arguments '%s'", strings.Join(unmatched, " "))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package args_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+)
+
+var _ = Describe("Examples", func() {
+	var stdout bytes.Buffer
+	var parser *args.ArgParser
+
+	BeforeEach(func() {
+		stdout.Reset()
+		parser = args.NewParser(args.Name("my-cli"), args.NoHelp(), args.WrapLen(80), args.Examples(
+			args.Example{CmdLine: "--endpoint http://localhost volume create my-vol", Desc: "Create a local volume"},
+		))
+		parser.HelpIO = &stdout
+		parser.AddOption("--endpoint").Help("The api endpoint")
+		parser.AddCommands(args.Command{
+			Name: "volume",
+			Help: "Manage volumes",
+			Subcommands: []args.Command{
+				{
+					Name: "create",
+					Help: "Create a new volume",
+					Options: func(subParser *args.ArgParser) {
+						subParser.AddOption("--size").IsInt().Help("The size of the volume")
+						subParser.AddArgument("name").Required().Help("The name of the volume")
+					},
+					Examples: []args.Example{{CmdLine: "--size 10 my-vol", Desc: "Create a 10GB volume"}},
+					Run: func(subParser *args.ArgParser, data interface{}) (int, error) {
+						return 0, nil
+					},
+				},
+			},
+		})
+	})
+
+	Describe("args.Examples()", func() {
+		It("Should list the examples in the help message", func() {
+			Expect(parser.GenerateHelp()).To(HaveSuffix("\nExamples:\n" +
+				"  # Create a local volume\n" +
+				"  my-cli --endpoint http://localhost volume create my-vol\n"))
+		})
+		It("Should list the examples of a command in the help of the command", func() {
+			parser.AddCommand("delete", func(subParser *args.ArgParser, data interface{}) (int, error) {
+				subParser.PrintHelp()
+				return 0, nil
+			}).Example("my-vol", "Delete a volume")
+
+			cmdLine := []string{"delete"}
+			_, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(stdout.String()).To(ContainSubstring("Examples:\n  # Delete a volume\n  my-cli delete my-vol\n"))
+		})
+		It("Should include the examples in the markdown reference", func() {
+			var buf bytes.Buffer
+			Expect(parser.GenerateMarkdown(&buf)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("## Examples\n\nCreate a 10GB volume\n\n" +
+				"```\nmy-cli volume create --size 10 my-vol\n```\n"))
+		})
+	})
+
+	Describe("ArgParser.ValidateExamples()", func() {
+		It("Should parse each example", func() {
+			Expect(parser.ValidateExamples()).To(Succeed())
+
+			// The parser should be unaffected by the examples
+			opts, err := parser.Parse(&[]string{})
+			Expect(err).To(BeNil())
+			Expect(opts.String("endpoint")).To(Equal(""))
+		})
+		It("Should return an error if an example no longer parses", func() {
+			parser.AddCommands(args.Command{
+				Name: "status",
+				Options: func(subParser *args.ArgParser) {
+					subParser.AddOption("--count").IsInt()
+				},
+				Examples: []args.Example{{CmdLine: "--count many"}},
+			})
+			err := parser.ValidateExamples()
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("while parsing example 'my-cli status --count many'"))
+		})
+		It("Should return an error if an example uses an unknown option", func() {
+			parser.AddCommands(args.Command{
+				Name: "status",
+				Options: func(subParser *args.ArgParser) {
+					subParser.AddOption("--size").IsInt()
+				},
+				Examples: []args.Example{{CmdLine: "--sise 10 --no-such-flag"}},
+			})
+			err := parser.ValidateExamples()
+			Expect(err).To(MatchError("while parsing example 'my-cli status --sise 10 --no-such-flag': " +
+				"unrecognized arguments '--sise 10 --no-such-flag'"))
+		})
+		It("Should check the examples of AddCommand() commands against the inherited options", func() {
+			parser.AddOption("--retries").IsInt()
+			parser.AddCommand("delete", func(subParser *args.ArgParser, data interface{}) (int, error) {
+				return 0, nil
+			}).Example("--force my-vol", "Delete a volume").Example("--retries many my-vol", "")
+
+			err := parser.ValidateExamples()
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("while parsing example 'my-cli delete --retries many my-vol'"))
+		})
+	})
+})
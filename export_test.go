@@ -0,0 +1,11 @@
+package args
+
+import "runtime/debug"
+
+// Replace the build info read by Version() and VersionFromBuildInfo(), returns a
+// function which restores the original
+func SetReadBuildInfo(readBuildInfo func() (*debug.BuildInfo, bool)) func() {
+	prev := readBuildInfoFunc
+	readBuildInfoFunc = readBuildInfo
+	return func() { readBuildInfoFunc = prev }
+}
@@ -0,0 +1,170 @@
+package args
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var roffEscaper = strings.NewReplacer(`\`, `\e`, "-", `\-`)
+
+// Generate a man page in roff format for this parser. The page includes NAME, SYNOPSIS,
+// DESCRIPTION, COMMANDS, ARGUMENTS, OPTIONS, ENVIRONMENT, FILES and EXAMPLES sections
+// generated from the rules of the parser.
+//
+//	file, _ := os.Create("my-cli.1")
+//	err := parser.GenerateManPage(1, file)
+func (self *ArgParser) GenerateManPage(section int, w io.Writer) error {
+	var buf bytes.Buffer
+	name := self.manName()
+
+	fmt.Fprintf(&buf, ".TH \"%s\" \"%d\"\n", roffEscape(strings.ToUpper(name)), section)
+
+	buf.WriteString(".SH NAME\n")
+	summary := strings.SplitN(strings.TrimSpace(self.Description), "\n", 2)[0]
+	if summary != "" {
+		fmt.Fprintf(&buf, "%s \\- %s\n", roffEscape(name), roffEscape(summary))
+	} else {
+		buf.WriteString(roffEscape(name) + "\n")
+	}
+
+	buf.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&buf, ".B %s\n", roffEscape(name))
+	synopsis := strings.TrimSpace(self.GenerateUsage(IsOption) + " " + self.GenerateUsage(IsArgument))
+	if len(self.helpRules(IsCommand)) != 0 {
+		synopsis += " <command>"
+	}
+	buf.WriteString(roffText(synopsis) + "\n")
+
+	if self.Description != "" {
+		buf.WriteString(".SH DESCRIPTION\n")
+		if HasFlags(self.flags, IsFormated) {
+			// Preserve the formatting the user provided
+			buf.WriteString(".nf\n" + roffText(self.Description) + "\n.fi\n")
+		} else {
+			buf.WriteString(roffText(self.Description) + "\n")
+		}
+	}
+
+	commands := self.helpRules(IsCommand)
+	if len(commands) != 0 {
+		buf.WriteString(".SH COMMANDS\n")
+		writeManRules(&buf, commands)
+	}
+
+	arguments := self.helpRules(IsArgument)
+	if len(arguments) != 0 {
+		buf.WriteString(".SH ARGUMENTS\n")
+		writeManRules(&buf, arguments)
+	}
+
+	options := append(self.helpRules(IsOption), self.globalRules()...)
+	if len(options) != 0 {
+		buf.WriteString(".SH OPTIONS\n")
+		writeManRules(&buf, options)
+	}
+
+	var environ, files []string
+	for _, rule := range append(options, self.helpRules(IsConfig)...) {
+		for _, env := range rule.envVars() {
+			environ = append(environ, fmt.Sprintf(".TP\n.B %s\n%s\n", roffEscape(env),
+				roffText(rule.RuleDesc)))
+		}
+		if rule.HasFlag(IsFilePath) && rule.Default != nil {
+			files = append(files, fmt.Sprintf(".TP\n.I %s\n%s\n", roffEscape(*rule.Default),
+				roffText(rule.RuleDesc)))
+		}
+	}
+	if len(environ) != 0 {
+		buf.WriteString(".SH ENVIRONMENT\n" + strings.Join(environ, ""))
+	}
+	if len(files) != 0 {
+		buf.WriteString(".SH FILES\n" + strings.Join(files, ""))
+	}
+
+	if len(self.examples) != 0 {
+		buf.WriteString(".SH EXAMPLES\n")
+		for _, example := range self.examples {
+			fmt.Fprintf(&buf, ".TP\n.B %s\n%s\n", roffEscape(self.exampleCmdLine(example)), roffText(example.Desc))
+		}
+	}
+
+	var seeAlso []string
+	for _, rule := range commands {
+		if rule.CommandDef != nil {
+			seeAlso = append(seeAlso, fmt.Sprintf("\\fB%s\\fR(%d)",
+				roffEscape(manPageName(self.commandPath(rule))), section))
+		}
+	}
+	if len(seeAlso) != 0 {
+		buf.WriteString(".SH SEE ALSO\n" + strings.Join(seeAlso, ", ") + "\n")
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Write a man page for this parser and a page for each sub command defined
+// with AddCommands() into the directory provided. IE: 'my-cli.1' and 'my-cli-volume.1'
+func (self *ArgParser) GenerateManPages(section int, dir string) error {
+	fileName := filepath.Join(dir, fmt.Sprintf("%s.%d", manPageName(self.manName()), section))
+	file, err := os.Create(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "while creating man page '%s'", fileName)
+	}
+	defer file.Close()
+
+	if err := self.GenerateManPage(section, file); err != nil {
+		return errors.Wrapf(err, "while writing man page '%s'", fileName)
+	}
+
+	for _, rule := range self.helpRules(IsCommand) {
+		if parser := self.commandDefParser(rule); parser != nil {
+			if err := parser.GenerateManPages(section, dir); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (self *ArgParser) manName() string {
+	if self.Name == "" {
+		return path.Base(os.Args[0])
+	}
+	return self.Name
+}
+
+// Returns the name of the man page for a command path. IE: 'my-cli volume' = 'my-cli-volume'
+func manPageName(name string) string {
+	return strings.Replace(name, " ", "-", -1)
+}
+
+func writeManRules(buf *bytes.Buffer, rules Rules) {
+	for _, rule := range rules {
+		flags, message := rule.GenerateHelp()
+		fmt.Fprintf(buf, ".TP\n\\fB%s\\fR\n%s\n", roffEscape(strings.TrimSpace(flags)), roffText(message))
+	}
+}
+
+// Escape characters roff would otherwise interpret
+func roffEscape(text string) string {
+	return roffEscaper.Replace(text)
+}
+
+// Escape the text and ensure no line begins with a roff control character
+func roffText(text string) string {
+	lines := strings.Split(roffEscape(text), "\n")
+	for idx, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[idx] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
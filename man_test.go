@@ -0,0 +1,79 @@
+package args_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+)
+
+var _ = Describe("ArgParser", func() {
+	var parser *args.ArgParser
+
+	BeforeEach(func() {
+		parser = args.NewParser(args.Name("my-cli"), args.EnvPrefix("APP_"),
+			args.Desc("Manage volumes in the cloud"))
+		parser.AddOption("--power-level").Alias("-p").Default("10000").Env("POWER_LEVEL").
+			Help("Specify our power level")
+		parser.AddOption("--config").Alias("-c").IsFilePath().Default("/etc/my-cli.ini").
+			Help("Path to the config file")
+		parser.AddCommands(args.Command{
+			Name: "volume",
+			Help: "Manage volumes",
+			Subcommands: []args.Command{
+				{
+					Name: "create",
+					Help: "Create a volume",
+					Options: func(subParser *args.ArgParser) {
+						subParser.AddArgument("name").Required().Help("The name of the volume")
+					},
+				},
+			},
+		})
+	})
+
+	Describe("ArgParser.GenerateManPage()", func() {
+		It("Should generate a roff man page", func() {
+			var buf bytes.Buffer
+			Expect(parser.GenerateManPage(1, &buf)).To(Succeed())
+			page := buf.String()
+			Expect(page).To(ContainSubstring(".TH \"MY\\-CLI\" \"1\"\n"))
+			Expect(page).To(ContainSubstring(".SH NAME\nmy\\-cli \\- Manage volumes in the cloud\n"))
+			Expect(page).To(ContainSubstring(".SH SYNOPSIS\n.B my\\-cli\n[OPTIONS] <command>\n"))
+			Expect(page).To(ContainSubstring(".TP\n\\fB\\-p, \\-\\-power\\-level\\fR\n" +
+				"Specify our power level (Default=10000, Env=APP_POWER_LEVEL)\n"))
+			Expect(page).To(ContainSubstring(".SH ENVIRONMENT\n.TP\n.B APP_POWER_LEVEL\n"))
+			Expect(page).To(ContainSubstring(".SH FILES\n.TP\n.I /etc/my\\-cli.ini\n"))
+			Expect(page).To(ContainSubstring(".SH COMMANDS\n.TP\n\\fBvolume\\fR\nManage volumes\n"))
+			Expect(page).To(ContainSubstring(".SH SEE ALSO\n\\fBmy\\-cli\\-volume\\fR(1)\n"))
+		})
+		It("Should preserve formated descriptions", func() {
+			parser := args.NewParser(args.Name("my-cli"), args.Desc("First line\n.Second line", args.IsFormated))
+			parser.AddOption("--power-level")
+			var buf bytes.Buffer
+			Expect(parser.GenerateManPage(8, &buf)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring(".SH DESCRIPTION\n.nf\nFirst line\n\\&.Second line\n.fi\n"))
+		})
+	})
+
+	Describe("ArgParser.GenerateManPages()", func() {
+		It("Should write a page for each sub command", func() {
+			dir, err := ioutil.TempDir("", "args-man")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(dir)
+
+			Expect(parser.GenerateManPages(1, dir)).To(Succeed())
+			content, err := ioutil.ReadFile(filepath.Join(dir, "my-cli-volume-create.1"))
+			Expect(err).To(BeNil())
+			Expect(string(content)).To(ContainSubstring(
+				".SH NAME\nmy\\-cli volume create \\- Create a volume\n"))
+			Expect(string(content)).To(ContainSubstring(".SH ARGUMENTS\n.TP\n\\fBname\\fR\nThe name of the volume\n"))
+			Expect(filepath.Join(dir, "my-cli.1")).To(BeAnExistingFile())
+			Expect(filepath.Join(dir, "my-cli-volume.1")).To(BeAnExistingFile())
+		})
+	})
+})
@@ -3,15 +3,19 @@ package args
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"sort"
 	"sync"
+	"text/template"
 
 	"strings"
+	"time"
 
 	"github.com/fatih/structs"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 )
 
 var regexIsOptional = regexp.MustCompile(`^(\W+)([\w|-]*)$`)
@@ -26,8 +30,17 @@ type ArgParser struct {
 	WordWrap             int
 	IsSubParser          bool
 	StopParsingOnCommand bool
-	HelpIO               *os.File
+	GracePeriod          time.Duration
+	InputIO              io.Reader
+	HelpIO               io.Writer
+	ErrorIO              io.Writer
+	Exit                 func(int)
+	HelpExitCode         int
+	UsageExitCode        int
+	LookupEnv            func(string) (string, bool)
 	helpAdded            bool
+	helpConfigAdded      bool
+	helpEnvAdded         bool
 	mutex                sync.Mutex
 	AddHelpOption        bool
 	args                 []string
@@ -39,16 +52,38 @@ type ArgParser struct {
 	attempts             int
 	log                  StdLogger
 	flags                int64
+	hooks                []commandHooks
+	ctx                  context.Context
+	inherited            Rules
+	pluginPrefix         string
+	prompt               string
+	defaultCommand       string
+	helpTemplate         *template.Template
+	usageTemplate        *template.Template
+	historyFile          string
+	groupDesc            map[string]string
+	groupOrder           []string
+	version              *VersionInfo
+	versionTemplate      *template.Template
+	versionAdded         bool
+	colorMode            ColorMode
+	style                *Style
+	colorAdded           bool
+	examples             []Example
+	autoEnv              bool
 }
 
 // Creates a new instance of the argument parser
 func NewParser(modifiers ...ParseModifier) *ArgParser {
 	parser := &ArgParser{
-		WordWrap:      200,
 		mutex:         sync.Mutex{},
 		log:           DefaultLogger,
 		AddHelpOption: true,
+		InputIO:       os.Stdin,
 		HelpIO:        os.Stdout,
+		ErrorIO:       os.Stderr,
+		Exit:          os.Exit,
+		UsageExitCode: 2,
 	}
 	for _, modify := range modifiers {
 		modify(parser)
@@ -70,13 +105,29 @@ func (self *ArgParser) SubParser() *ArgParser {
 		}
 	}
 
+	// structs can not set interface fields, so copy them here
+	parser.InputIO = self.InputIO
+	parser.HelpIO = self.HelpIO
+	parser.ErrorIO = self.ErrorIO
+
 	parser.args = self.args
-	parser.rules = self.rules
+	// Copy the rules so removing commands does not modify the parent's rules
+	parser.rules = append(Rules{}, self.rules...)
 	parser.log = self.log
 	parser.helpAdded = self.helpAdded
 	parser.AddHelpOption = self.AddHelpOption
 	parser.options = self.options
 	parser.flags = self.flags
+	parser.ctx = self.ctx
+	parser.helpTemplate = self.helpTemplate
+	parser.usageTemplate = self.usageTemplate
+	parser.groupDesc = self.groupDesc
+	parser.groupOrder = self.groupOrder
+	parser.colorMode = self.colorMode
+	parser.style = self.style
+	parser.colorAdded = self.colorAdded
+	parser.autoEnv = self.autoEnv
+	parser.hooks = append([]commandHooks{}, self.hooks...)
 
 	// Remove all Commands from our rules
 	for i := len(parser.rules) - 1; i >= 0; i-- {
@@ -85,12 +136,44 @@ func (self *ArgParser) SubParser() *ArgParser {
 			parser.rules = append(parser.rules[:i], parser.rules[i+1:]...)
 		}
 	}
+	// Remember which rules belong to the parent
+	parser.inherited = append(Rules{}, parser.rules...)
 	// Clear the selected Commands
 	parser.Command = nil
 	parser.IsSubParser = true
 	return parser
 }
 
+// The value, flags and count of a rule saved by saveRules()
+type ruleState struct {
+	value interface{}
+	flags int64
+	count int
+}
+
+// The state of the rules of a parser saved by saveRules()
+type rulesState map[*Rule]ruleState
+
+// Adds the options the parser adds during Parse() and returns the current state of our
+// rules, such that the parser can parse the command line again after restore() is called
+func (self *ArgParser) saveRules() rulesState {
+	self.addHelpOption()
+	self.addVersionOption()
+	self.addColorOption()
+	state := make(rulesState)
+	for _, rule := range self.rules {
+		state[rule] = ruleState{value: rule.Value, flags: rule.Flags, count: rule.Count}
+	}
+	return state
+}
+
+// Restore the rules to the state they were in when saveRules() was called
+func (self rulesState) restore() {
+	for rule, state := range self {
+		rule.Value, rule.Flags, rule.Count = state.value, state.flags, state.count
+	}
+}
+
 func (self *ArgParser) SetLog(logger StdLogger) {
 	self.log = logger
 }
@@ -99,6 +182,15 @@ func (self *ArgParser) GetLog() StdLogger {
 	return self.log
 }
 
+// Returns the context passed to ParseAndRunContext() or context.Background()
+// if the command was not run with a context
+func (self *ArgParser) Context() context.Context {
+	if self.ctx == nil {
+		return context.Background()
+	}
+	return self.ctx
+}
+
 func (self *ArgParser) SetDesc(desc string) {
 	self.Description = desc
 }
@@ -144,6 +236,20 @@ func (self *ArgParser) ValidateRules() error {
 			}
 		}
 	}
+
+	// Derived environment variable names could collide with each other or a name given via Env()
+	if self.autoEnv {
+		envs := make(map[string]*Rule)
+		for _, rule := range self.rules {
+			for _, env := range rule.envVars() {
+				if other, ok := envs[env]; ok && other != rule {
+					return errors.Errorf("Duplicate environment variable '%s' read by '%s' and '%s'; "+
+						"use Env() or NoAutoEnv() to resolve", env, other.Name, rule.Name)
+				}
+				envs[env] = rule
+			}
+		}
+	}
 	return nil
 }
 
@@ -196,6 +302,10 @@ func (self *ArgParser) AddRule(name string, modifier *RuleModifier) *RuleModifie
 
 	// Apply the Environment Prefix to all new rules
 	rule.EnvPrefix = self.EnvPrefix
+	// Options and config keys read a derived environment variable if AutoEnv() was given
+	if self.autoEnv && rule.HasFlag(IsOption|IsConfig) {
+		rule.autoEnv = true
+	}
 
 	// If name begins with a non word character, assume it's an optional argument
 	if regexIsOptional.MatchString(name) {
@@ -265,25 +375,131 @@ func (self *ArgParser) ParseAndRun(args *[]string, data interface{}) (int, error
 	if err != nil {
 		if IsHelpError(err) {
 			self.PrintHelp()
-			return 1, nil
+			return self.HelpExitCode, nil
+		}
+		if IsVersionError(err) {
+			self.PrintVersion()
+			return 0, nil
 		}
-		return 1, err
+		if IsCompletion(err) {
+			return 0, nil
+		}
+		return self.UsageExitCode, err
 	}
 	return self.RunCommand(data)
 }
 
 // Run the command chosen via the command line, err != nil
-// if no command was found on the commandline
+// if no command was found on the commandline. If the command returns
+// an ExitError the error is printed to ErrorIO and the code returned
 func (self *ArgParser) RunCommand(data interface{}) (int, error) {
 	// If user didn't provide a command via the commandline
 	if self.Command == nil {
-		self.PrintHelp()
-		return 1, nil
+		// Run a plugin from the PATH if plugins are enabled
+		if retCode, ok, err := self.runPlugin(); ok {
+			return retCode, err
+		}
+		if self.defaultCommand == "" {
+			self.PrintHelp()
+			return self.UsageExitCode, nil
+		}
+		self.Command = self.matchAlias(self.defaultCommand, IsCommand)
+		if self.Command == nil {
+			return 1, errors.Errorf("default command '%s' does not exist", self.defaultCommand)
+		}
 	}
 
 	parser := self.SubParser()
-	retCode, err := self.Command.CommandFunc(parser, data)
-	return retCode, err
+	// Include the full command path in the usage line of the sub parser
+	parser.Name = self.commandPath(self.Command)
+	parser.examples = self.Command.Examples
+
+	// Commands added via AddCommand() run with the hooks of any parent commands
+	if self.Command.CommandDef == nil {
+		return parser.exitWith(parser.runWithHooks(self.Command.CommandFunc, data))
+	}
+	return parser.exitWith(self.Command.CommandFunc(parser, data))
+}
+
+// If the command returned an ExitError, print the error to ErrorIO and
+// return the exit code of the error instead of the error
+func (self *ArgParser) exitWith(retCode int, err error) (int, error) {
+	exitErr, ok := AsExitError(err)
+	if !ok {
+		return retCode, err
+	}
+	// Include any context the ExitError was wrapped with, an ExitError without an error prints nothing
+	if exitErr.Err != nil || err != error(exitErr) {
+		self.printError("%s: %s", self.Name, err)
+	}
+	if exitErr.Usage {
+		fmt.Fprintln(self.ErrorIO, self.usageLine())
+		return self.UsageExitCode, nil
+	}
+	return exitErr.Code, nil
+}
+
+// Returns the program name followed by the name of the command
+func (self *ArgParser) commandPath(rule *Rule) string {
+	return strings.TrimSpace(self.Name + " " + rule.Aliases[0])
+}
+
+// Adds a built-in 'help' command such that `my-cli help volume create` prints the same
+// help message as `my-cli volume create --help`. Since the help is generated by the
+// command it will walk nested sub parsers created with ParseAndRun()
+func (self *ArgParser) AddHelpCommand() *RuleModifier {
+	return self.AddCommand("help", func(subParser *ArgParser, data interface{}) (int, error) {
+		// Dispatch the remaining arguments through our command tree asking for help
+		cmdLine := append(subParser.GetArgs(), "--help")
+		self.Command = nil
+		return self.ParseAndRun(&cmdLine, data)
+	}).Help("Display help for a command and exit")
+}
+
+// Run the named command when no command is given on the command line. Any arguments
+// left on the command line are parsed by the sub parser of the default command
+//
+//	parser.AddCommand("status", status)
+//	// `mycli -v` runs `mycli -v status`
+//	parser.DefaultCommand("status")
+func (self *ArgParser) DefaultCommand(name string) {
+	self.defaultCommand = name
+}
+
+// Describe the options in the group, the description is displayed under the
+// group's section title in the help message
+//
+//	parser.AddOption("--host").InGroup("database")
+//	parser.GroupDesc("database", "Connection settings for the database")
+func (self *ArgParser) GroupDesc(group, desc string) {
+	if self.groupDesc == nil {
+		self.groupDesc = make(map[string]string)
+	}
+	self.groupDesc[group] = desc
+}
+
+// Set the order option groups are displayed in the help message. Options in the default
+// group are always listed first, groups not listed follow in the order they were declared
+//
+//	parser.GroupOrder("database", "auth")
+func (self *ArgParser) GroupOrder(groups ...string) {
+	self.groupOrder = groups
+}
+
+// Returns the groups given in the order they should be displayed, the default group is always first
+func (self *ArgParser) orderGroups(groups []string) []string {
+	var results []string
+	for _, group := range append([]string{DefaultOptionGroup}, self.groupOrder...) {
+		if containsString(group, groups) && !containsString(group, results) {
+			results = append(results, group)
+		}
+	}
+	for _, group := range groups {
+		if !containsString(group, results) {
+			results = append(results, group)
+		}
+	}
+	return results
 }
 
 func (self *ArgParser) HasHelpOption() bool {
@@ -315,31 +531,58 @@ func (self *ArgParser) ParseSimple(args *[]string) *Options {
 		self.PrintHelp()
 		return nil
 	}
-	// Print errors to stderr and include our help message
+	if IsVersionError(err) {
+		self.PrintVersion()
+		return nil
+	}
+	if IsCompletion(err) {
+		return nil
+	}
+	// Print errors to ErrorIO and include our help message
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err.Error())
+		self.printError("%s", err)
 		return nil
 	}
 	return opt
 }
 
-// Parse the commandline, but also print help and exit if the user asked for --help
+// Parse the commandline, but also print help and exit with HelpExitCode if the user asked
+// for --help. If there was an error parsing, print the error to ErrorIO and exit with UsageExitCode
 func (self *ArgParser) ParseOrExit(args *[]string) *Options {
 	opt, err := self.Parse(args)
 
 	// We could have a non critical error, in addition to the user asking for help
 	if opt != nil && opt.Bool("help") {
 		self.PrintHelp()
-		os.Exit(1)
+		self.exit(self.HelpExitCode)
+		return nil
+	}
+	if IsVersionError(err) {
+		self.PrintVersion()
+		self.exit(0)
+		return nil
 	}
-	// Print errors to stderr and include our help message
+	if IsCompletion(err) {
+		self.exit(0)
+		return nil
+	}
+	// Print errors to ErrorIO and include our help message
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err.Error())
-		os.Exit(1)
+		self.printError("%s", err)
+		self.exit(self.UsageExitCode)
+		return nil
 	}
 	return opt
 }
 
+// Exit via the Exit function provided by the user, os.Exit() if not set
+func (self *ArgParser) exit(code int) {
+	if self.Exit == nil {
+		os.Exit(code)
+	}
+	self.Exit(code)
+}
+
 // Parses command line arguments using os.Args if 'args' is nil
 func (self *ArgParser) Parse(args *[]string) (*Options, error) {
 	if args != nil {
@@ -349,12 +592,35 @@ func (self *ArgParser) Parse(args *[]string) (*Options, error) {
 		self.args = copyStringSlice(os.Args[1:])
 	}
 
+	self.addHelpOption()
+	self.addVersionOption()
+	self.addColorOption()
+
+	// The generated completion scripts ask for candidates via the hidden __complete command
+	if !self.IsSubParser && len(self.args) != 0 && self.args[0] == CompleteCommand {
+		self.complete(self.args[1:])
+		return nil, &CompletionError{}
+	}
+	return self.parseUntil("--")
+}
+
+func (self *ArgParser) addHelpOption() {
 	if self.AddHelpOption && !self.HasHelpOption() {
 		// Add help option if --help or -h are not already taken by other options
-		self.AddOption("--help").Alias("-h").IsTrue().Help("Display this help message and exit")
+		self.AddOption("--help").Alias("-h").IsTrue().Persistent().NoAutoEnv().Help("Display this help message and exit")
 		self.helpAdded = true
 	}
-	return self.parseUntil("--")
+	// Add --help-config if we have config keys to describe
+	if self.AddHelpOption && !self.IsSubParser && len(self.helpRules(IsConfig|IsConfigGroup)) != 0 &&
+		self.GetRule("help-config") == nil {
+		self.AddOption("--help-config").IsTrue().NoAutoEnv().Help("Display the configuration keys and exit")
+		self.helpConfigAdded = true
+	}
+	// Add --help-env if we have environment variables to describe
+	if self.AddHelpOption && !self.IsSubParser && len(self.envRules()) != 0 && self.GetRule("help-env") == nil {
+		self.AddOption("--help-env").IsTrue().NoAutoEnv().Help("Display the environment variables in .env format and exit")
+		self.helpEnvAdded = true
+	}
 }
 
 func (self *ArgParser) parseUntil(terminator string) (*Options, error) {
@@ -370,8 +636,9 @@ func (self *ArgParser) parseUntil(terminator string) (*Options, error) {
 		return nil, err
 	}
 
-	// Sort the rules so positional rules are parsed last
-	sort.Sort(self.rules)
+	// Sort the rules so positional rules are parsed last, a stable sort
+	// preserves the order options and commands were declared in
+	sort.Stable(self.rules)
 
 	// Process command line arguments until we find our terminator
 	for ; self.idx < len(self.args); self.idx++ {
@@ -393,6 +660,14 @@ func (self *ArgParser) parseUntil(terminator string) (*Options, error) {
 		}
 		//fmt.Printf("Found rule - %+v\n", rule)
 
+		// If we already found a command token on the commandline
+		if rule.HasFlag(IsCommand) && self.Command != nil {
+			// Ignore this match and leave it for the sub parser,
+			// it must be a sub command or a positional argument
+			rule.ClearFlag(Seen)
+			continue
+		}
+
 		// Remove the argument so a sub processor won't process it again, this avoids confusing behavior
 		// for sub parsers. IE: [prog -o option sub-command -o option] the first -o will not
 		// be confused with the second -o since we remove it from args here
@@ -401,11 +676,6 @@ func (self *ArgParser) parseUntil(terminator string) (*Options, error) {
 
 		// If we matched a command
 		if rule.HasFlag(IsCommand) {
-			// If we already found a command token on the commandline
-			if self.Command != nil {
-				// Ignore this match, it must be a sub command or a positional argument
-				rule.ClearFlag(Seen)
-			}
 			self.Command = rule
 			// If user asked us to stop parsing arguments after finding a command
 			// This might be useful if the user wants arguments found before the command
@@ -421,6 +691,15 @@ Apply:
 	// TODO: This should include the isRequired check
 	// return self.PostValidation(self.Apply(nil))
 
+	if self.versionAdded && opts.Bool("version") {
+		return opts, &VersionError{}
+	}
+
+	// Configuration and environment are shared by all commands, so print them even if a command was given
+	if (self.helpConfigAdded && opts.Bool("help-config")) || (self.helpEnvAdded && opts.Bool("help-env")) {
+		return opts, &HelpError{}
+	}
+
 	// When the user asks for --help
 	if self.helpAdded && opts.Bool("help") {
 		// Ignore the --help request if we see a sub command so the
@@ -442,6 +721,8 @@ func (self *ArgParser) Apply(values *Options) (*Options, error) {
 
 	// for each of the rules
 	for _, rule := range self.rules {
+		// Read environment variables via the parser, os.LookupEnv() if not set
+		rule.lookupEnv = self.LookupEnv
 		// Get the computed value
 		value, err := rule.ComputedValue(values)
 		if err != nil {
@@ -515,52 +796,100 @@ func (self *ArgParser) matchRules(rules Rules) (*Rule, error) {
 
 func (self *ArgParser) PrintRules() {
 	for _, rule := range self.rules {
-		fmt.Printf("Rule: %s - '%+v'\n", rule.Name, rule)
+		fmt.Fprintf(self.HelpIO, "Rule: %s - '%+v'\n", rule.Name, rule)
 	}
 }
 
 func (self *ArgParser) PrintHelp() {
+	// If the user asked for --help-config or --help-env
+	if opts := self.GetOpts(); opts != nil {
+		if self.helpConfigAdded && opts.Bool("help-config") {
+			fmt.Fprint(self.HelpIO, self.GenerateConfigHelp())
+			return
+		}
+		if self.helpEnvAdded && opts.Bool("help-env") {
+			fmt.Fprint(self.HelpIO, self.GenerateEnvFile())
+			return
+		}
+	}
 	fmt.Fprintln(self.HelpIO, self.GenerateHelp())
 }
 
 func (self *ArgParser) GenerateHelp() string {
+	if self.helpTemplate != nil {
+		return self.renderTemplate(self.helpTemplate, self.HelpData())
+	}
+
 	var result bytes.Buffer
 	// TODO: Improve this once we have arguments
 	// Super generic usage message
-	result.WriteString(fmt.Sprintf("Usage: %s %s %s\n", self.Name,
-		self.GenerateUsage(IsOption),
-		self.GenerateUsage(IsArgument)))
+	usage := self.usageLine()
+	if self.usageTemplate == nil {
+		usage = self.heading("Usage:") + strings.TrimPrefix(usage, "Usage:")
+	}
+	result.WriteString(usage + "\n")
 
 	if self.Description != "" {
 		result.WriteString("\n")
 		if HasFlags(self.flags, IsFormated) {
 			result.WriteString(self.Description)
 		} else {
-			result.WriteString(WordWrap(self.Description, 0, 80))
+			result.WriteString(WordWrap(self.Description, 0, self.wrapLen()))
 		}
 		result.WriteString("\n")
 	}
 
-	commands := self.GenerateHelpSection(IsCommand)
-	if commands != "" {
-		result.WriteString("\nCommands:\n")
-		result.WriteString(commands)
-	}
+	result.WriteString(self.generateCommandsHelp())
 
 	argument := self.GenerateHelpSection(IsArgument)
 	if argument != "" {
-		result.WriteString("\nArguments:\n")
+		result.WriteString("\n" + self.heading("Arguments:") + "\n")
 		result.WriteString(argument)
 	}
 
-	options := self.GenerateHelpSection(IsOption)
-	if options != "" {
-		result.WriteString("\nOptions:\n")
-		result.WriteString(options)
+	result.WriteString(self.generateOptionsHelp())
+
+	global := self.globalRules()
+	if len(global) != 0 {
+		result.WriteString("\n" + self.heading("Global Options:") + "\n")
+		result.WriteString(self.generateRulesHelp(global, global.helpIndent()))
+	}
+
+	config := self.GenerateHelpSection(IsConfig | IsConfigGroup)
+	if config != "" {
+		result.WriteString("\n" + self.heading("Configuration:") + "\n")
+		result.WriteString(config)
+	}
+
+	environ := self.GenerateEnvHelp()
+	if environ != "" {
+		result.WriteString("\n" + environ)
+	}
+
+	examples := self.GenerateExamplesHelp()
+	if examples != "" {
+		result.WriteString("\n" + examples)
 	}
 	return result.String()
 }
 
+// Generate the 'Configuration:' section of the help message which describes the config keys and
+// config groups that can be provided by a config file or backend. This is printed by --help-config
+func (self *ArgParser) GenerateConfigHelp() string {
+	config := self.GenerateHelpSection(IsConfig | IsConfigGroup)
+	if config == "" {
+		return ""
+	}
+	return self.heading("Configuration:") + "\n" + config
+}
+
+func (self *ArgParser) usageLine() string {
+	if self.usageTemplate != nil {
+		return self.HelpData().Usage
+	}
+	return fmt.Sprintf("Usage: %s %s %s", self.Name, self.GenerateUsage(IsOption), self.GenerateUsage(IsArgument))
+}
+
 func (self *ArgParser) GenerateUsage(flags int64) string {
 	var result bytes.Buffer
 
@@ -571,7 +900,7 @@ func (self *ArgParser) GenerateUsage(flags int64) string {
 	}
 
 	for _, rule := range self.rules {
-		if !rule.HasFlag(flags) {
+		if !rule.HasFlag(flags) || rule.HasFlag(IsHidden) {
 			continue
 		}
 		result.WriteString(" " + rule.GenerateUsage())
@@ -585,29 +914,104 @@ type HelpMsg struct {
 }
 
 func (self *ArgParser) GenerateHelpSection(flags int64) string {
-	var result bytes.Buffer
-	var options []HelpMsg
+	rules := self.helpRules(flags)
+	return self.generateRulesHelp(rules, rules.helpIndent())
+}
 
-	// Ask each rule to generate a Help message for the options
-	maxLen := 0
+// Returns the rules with the given flags that should be displayed in the help message,
+// rules inherited from a parent parser are not included
+func (self *ArgParser) helpRules(flags int64) Rules {
+	var results Rules
 	for _, rule := range self.rules {
-		if !rule.HasFlag(flags) {
-			continue
+		if rule.HasFlag(flags) && !rule.HasFlag(IsHidden) && !self.isInherited(rule) {
+			results = append(results, rule)
+		}
+	}
+	return results
+}
+
+// Returns the persistent rules inherited from our parent parsers
+func (self *ArgParser) globalRules() Rules {
+	var results Rules
+	for _, rule := range self.inherited {
+		if rule.HasFlag(IsPersistent) && !rule.HasFlag(IsHidden) {
+			results = append(results, rule)
+		}
+	}
+	return results
+}
+
+// Returns true if the rule was inherited from a parent parser
+func (self *ArgParser) isInherited(rule *Rule) bool {
+	for _, inherited := range self.inherited {
+		if inherited == rule {
+			return true
 		}
+	}
+	return false
+}
+
+func (self *ArgParser) generateRulesHelp(rules Rules, indent int) string {
+	var result bytes.Buffer
+	wrapLen := self.wrapLen()
+
+	// If the flags would squeeze the description column, flags that
+	// don't fit in half the width are put on their own line
+	if indent > wrapLen/2 {
+		indent = rules.helpIndentWithin(wrapLen / 2)
+	}
+
+	for _, rule := range rules {
 		flags, message := rule.GenerateHelp()
-		if len(flags) > maxLen {
-			maxLen = len(flags)
+		message = WordWrap(self.styleMessage(rule, message), indent, wrapLen)
+		width := DisplayWidth(flags)
+		flags = self.styleFlags(rule, flags)
+		if width+3 > indent {
+			result.WriteString(fmt.Sprintf("%s\n%s%s\n", flags, strings.Repeat(" ", indent), message))
+			continue
 		}
-		options = append(options, HelpMsg{flags, message})
+		result.WriteString(flags + strings.Repeat(" ", indent-width) + message + "\n")
 	}
+	return result.String()
+}
 
-	// Set our indent length
-	indent := maxLen + 3
-	flagFmt := fmt.Sprintf("%%-%ds%%s\n", indent)
+// Generate the help for options with a titled section for each option group, options
+// in the default group are listed first followed by the groups in display order
+func (self *ArgParser) generateOptionsHelp() string {
+	var result bytes.Buffer
+	options := self.helpRules(IsOption)
+	indent := options.helpIndent()
+
+	for _, group := range self.orderGroups(options.groups()) {
+		if group == DefaultOptionGroup {
+			result.WriteString("\n" + self.heading("Options:") + "\n")
+		} else {
+			result.WriteString("\n" + self.heading(titleCase(group)+" Options:") + "\n")
+		}
+		if desc, ok := self.groupDesc[group]; ok {
+			result.WriteString("  " + WordWrap(desc, 2, self.wrapLen()) + "\n")
+		}
+		result.WriteString(self.generateRulesHelp(options.inGroup(group), indent))
+	}
+	return result.String()
+}
+
+// Generate the help for commands with a titled section for each category
+// in the order they were declared, uncategorized commands are listed last
+func (self *ArgParser) generateCommandsHelp() string {
+	var result bytes.Buffer
+	commands := append(self.helpRules(IsCommand), self.pluginRules()...)
+	indent := commands.helpIndent()
+
+	for _, category := range commands.categories() {
+		result.WriteString("\n" + self.heading(category+":") + "\n")
+		result.WriteString(self.generateRulesHelp(commands.inCategory(category), indent))
+	}
 
-	for _, opt := range options {
-		message := WordWrap(opt.Message, indent, self.WordWrap)
-		result.WriteString(fmt.Sprintf(flagFmt, opt.Flags, message))
+	uncategorized := commands.inCategory("")
+	if len(uncategorized) != 0 {
+		result.WriteString("\n" + self.heading("Commands:") + "\n")
+		result.WriteString(self.generateRulesHelp(uncategorized, indent))
 	}
 	return result.String()
 }
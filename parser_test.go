@@ -1,10 +1,14 @@
 package args_test
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
 	"os"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"github.com/thrawn01/args"
 )
 
@@ -533,6 +537,40 @@ var _ = Describe("ArgParser", func() {
 			// The new config has the value applied
 			Expect(newOpt.Int("power-level")).To(Equal(3))
 		})
+		It("Should describe config keys in the help message", func() {
+			parser := args.NewParser(args.Name("my-cli"), args.EnvPrefix("APP_"), args.WrapLen(100))
+			parser.AddOption("--verbose").IsTrue().Help("Be verbose")
+			parser.AddConfig("power-level").Default("9000").Help("My help message")
+			parser.AddConfig("user").InGroup("database").Env("DB_USER").Help("database user")
+			parser.AddConfigGroup("endpoints").Help("Endpoints by service name")
+			_, err := parser.Parse(&[]string{})
+			Expect(err).To(BeNil())
+
+			Expect(parser.GenerateHelp()).To(ContainSubstring("\nConfiguration:\n" +
+				"  power-level   My help message (Default=9000, Key=/power-level)\n" +
+				"  user          database user (Group=database, Env=APP_DB_USER, Key=/database/user)\n" +
+				"  endpoints.*   Endpoints by service name (Key=/endpoints)\n"))
+		})
+		It("Should print only the configuration with --help-config", func() {
+			var stdout bytes.Buffer
+			parser := args.NewParser(args.Name("my-cli"))
+			parser.HelpIO = &stdout
+			parser.AddOption("--verbose").IsTrue().Help("Be verbose")
+			parser.AddConfig("power-level").Help("My help message")
+			called := false
+			parser.AddCommand("show", func(subParser *args.ArgParser, data interface{}) (int, error) {
+				called = true
+				return 0, nil
+			})
+
+			cmdLine := []string{"show", "--help-config"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(0))
+			Expect(called).To(Equal(false))
+			Expect(stdout.String()).To(Equal("Configuration:\n" +
+				"  power-level   My help message (Key=/power-level)\n"))
+		})
 	})
 	Describe("ArgParser.InGroup()", func() {
 		cmdLine := []string{"--power-level", "--hostname", "mysql.com"}
@@ -602,6 +640,32 @@ var _ = Describe("ArgParser", func() {
 				"--------------------------------------- over 80"))
 		})
 	})
+	Describe("ArgParser.GroupDesc()", func() {
+		It("Should list each option group in a titled section", func() {
+			parser := args.NewParser(args.Name("my-cli"), args.NoHelp(), args.WrapLen(80))
+			parser.AddOption("--verbose").IsTrue().Help("Be verbose")
+			parser.AddOption("--user").InGroup("database").Help("Database user")
+			parser.AddOption("--token").InGroup("auth").Help("Api token")
+			parser.AddOption("--host").InGroup("database").Help("Database host")
+			parser.GroupDesc("database", "Connection settings for the database")
+			parser.GroupOrder("auth")
+
+			Expect(parser.GenerateHelp()).To(Equal("Usage: my-cli [OPTIONS] \n" +
+				"\nOptions:\n" +
+				"  --verbose   Be verbose\n" +
+				"\nAuth Options:\n" +
+				"  --token     Api token\n" +
+				"\nDatabase Options:\n" +
+				"  Connection settings for the database\n" +
+				"  --user      Database user\n" +
+				"  --host      Database host\n"))
+		})
+		It("Should title case each word of the group name", func() {
+			parser := args.NewParser(args.Name("my-cli"), args.NoHelp(), args.WrapLen(80))
+			parser.AddOption("--size").InGroup("connection pool").Help("Pool size")
+			Expect(parser.GenerateHelp()).To(ContainSubstring("\nConnection Pool Options:\n  --size   Pool size\n"))
+		})
+	})
 	Describe("ArgParser.AddCommand()", func() {
 		It("Should run a command if seen on the command line", func() {
 			parser := args.NewParser()
@@ -737,6 +801,208 @@ var _ = Describe("ArgParser", func() {
 			Expect(called).To(Equal(1))
 		})
 	})
+	Describe("ArgParser.RunCommand()", func() {
+		var stderr bytes.Buffer
+		var parser *args.ArgParser
+
+		BeforeEach(func() {
+			stderr.Reset()
+			parser = args.NewParser(args.Name("my-cli"))
+			parser.ErrorIO = &stderr
+			parser.AddCommand("delete", func(subParser *args.ArgParser, data interface{}) (int, error) {
+				subParser.AddArgument("name")
+				opts := subParser.ParseSimple(nil)
+				if opts.String("name") == "" {
+					return args.ExitUsage(errors.New("a name is required"))
+				}
+				return args.Exit(3, errors.Wrap(errors.New("not found"), "while deleting"))
+			})
+		})
+
+		It("Should print the error and return the code of an ExitError", func() {
+			cmdLine := []string{"delete", "my-volume"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(3))
+			Expect(stderr.String()).To(Equal("my-cli delete: while deleting: not found\n"))
+		})
+		It("Should print the usage of the command for usage errors", func() {
+			parser.UsageExitCode = 64
+			cmdLine := []string{"delete"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(64))
+			Expect(stderr.String()).To(Equal("my-cli delete: a name is required\n" +
+				"Usage: my-cli delete [OPTIONS]  [name]\n"))
+		})
+		It("Should find an ExitError wrapped by another error", func() {
+			_, err := args.Exit(4, errors.New("boom"))
+			exitErr, ok := args.AsExitError(errors.Wrap(err, "while running"))
+			Expect(ok).To(Equal(true))
+			Expect(exitErr.Code).To(Equal(4))
+
+			exitErr, ok = args.AsExitError(fmt.Errorf("while running: %w", err))
+			Expect(ok).To(Equal(true))
+			Expect(exitErr.Code).To(Equal(4))
+		})
+		It("Should print the context an ExitError was wrapped with", func() {
+			parser.AddCommand("create", func(subParser *args.ArgParser, data interface{}) (int, error) {
+				_, err := args.Exit(5, errors.New("quota exceeded"))
+				return 1, errors.Wrap(err, "while creating")
+			})
+			cmdLine := []string{"create"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(5))
+			Expect(stderr.String()).To(Equal("my-cli create: while creating: quota exceeded\n"))
+		})
+		It("Should return the code of an ExitError wrapped with fmt.Errorf()", func() {
+			parser.AddCommand("create", func(subParser *args.ArgParser, data interface{}) (int, error) {
+				_, err := args.Exit(5, errors.New("quota exceeded"))
+				return 1, fmt.Errorf("while creating: %w", err)
+			})
+			cmdLine := []string{"create"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(5))
+			Expect(stderr.String()).To(Equal("my-cli create: while creating: quota exceeded\n"))
+		})
+	})
+	Describe("ArgParser.DefaultCommand()", func() {
+		var parser *args.ArgParser
+		var name string
+
+		BeforeEach(func() {
+			name = ""
+			parser = args.NewParser(args.Name("my-cli"))
+			parser.AddOption("--verbose").IsTrue()
+			parser.AddCommand("status", func(subParser *args.ArgParser, data interface{}) (int, error) {
+				subParser.AddArgument("name").Default("all")
+				opts := subParser.ParseSimple(nil)
+				name = opts.String("name")
+				return 0, nil
+			})
+			parser.AddCommand("stop", func(subParser *args.ArgParser, data interface{}) (int, error) {
+				name = "stop"
+				return 0, nil
+			})
+		})
+
+		It("Should run the default command if no command was given", func() {
+			parser.DefaultCommand("status")
+			cmdLine := []string{"--verbose", "my-service"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(0))
+			Expect(name).To(Equal("my-service"))
+		})
+		It("Should run the command given on the command line", func() {
+			parser.DefaultCommand("status")
+			cmdLine := []string{"stop"}
+			_, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(name).To(Equal("stop"))
+		})
+		It("Should return an error if the default command does not exist", func() {
+			parser.DefaultCommand("start")
+			cmdLine := []string{}
+			_, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("default command 'start' does not exist"))
+		})
+	})
+	Describe("ArgParser.ParseOrExit()", func() {
+		var stdout, stderr bytes.Buffer
+		var exitCode int
+		var parser *args.ArgParser
+
+		BeforeEach(func() {
+			stdout.Reset()
+			stderr.Reset()
+			exitCode = -1
+			parser = args.NewParser(args.Name("my-cli"))
+			parser.HelpIO = &stdout
+			parser.ErrorIO = &stderr
+			parser.Exit = func(code int) { exitCode = code }
+			parser.AddOption("--count").IsInt()
+		})
+
+		It("Should exit with HelpExitCode when asked for help", func() {
+			cmdLine := []string{"--help"}
+			Expect(parser.ParseOrExit(&cmdLine)).To(BeNil())
+			Expect(exitCode).To(Equal(0))
+			Expect(stdout.String()).To(ContainSubstring("Usage: my-cli [OPTIONS]"))
+		})
+		It("Should print the error to ErrorIO and exit with UsageExitCode", func() {
+			cmdLine := []string{"--count", "many"}
+			Expect(parser.ParseOrExit(&cmdLine)).To(BeNil())
+			Expect(exitCode).To(Equal(2))
+			Expect(stderr.String()).To(ContainSubstring("Invalid value for '--count'"))
+		})
+		It("Should honor configured exit codes", func() {
+			parser.UsageExitCode = 64
+			cmdLine := []string{"--count", "many"}
+			parser.ParseOrExit(&cmdLine)
+			Expect(exitCode).To(Equal(64))
+		})
+	})
+	Describe("ArgParser.AddHelpCommand()", func() {
+		var ioReader, ioWriter *os.File
+		var parser *args.ArgParser
+
+		readHelp := func() string {
+			ioWriter.Close()
+			content, _ := ioutil.ReadAll(ioReader)
+			return string(content)
+		}
+
+		BeforeEach(func() {
+			ioReader, ioWriter, _ = os.Pipe()
+			parser = args.NewParser(args.Name("my-cli"))
+			parser.HelpIO = ioWriter
+			parser.AddHelpCommand()
+			parser.AddCommand("volume", func(subParser *args.ArgParser, data interface{}) (int, error) {
+				subParser.AddCommand("create", func(subParser *args.ArgParser, data interface{}) (int, error) {
+					subParser.AddArgument("volume-name").Required().Help("The name of the volume")
+					_, err := subParser.Parse(nil)
+					if err != nil {
+						if args.IsHelpError(err) {
+							subParser.PrintHelp()
+							return 0, nil
+						}
+						return 1, err
+					}
+					return 0, nil
+				}).Help("Create a volume")
+				return subParser.ParseAndRun(nil, data)
+			}).Help("Manage volumes")
+		})
+
+		It("Should print help for a nested command", func() {
+			cmdLine := []string{"help", "volume", "create"}
+			_, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			help := readHelp()
+			Expect(help).To(ContainSubstring("Usage: my-cli volume create [OPTIONS]  <volume-name>"))
+			Expect(help).To(ContainSubstring("The name of the volume"))
+		})
+		It("Should print help for the root parser if no command is given", func() {
+			cmdLine := []string{"help"}
+			_, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			help := readHelp()
+			Expect(help).To(ContainSubstring("Usage: my-cli [OPTIONS]"))
+			Expect(help).To(ContainSubstring("Manage volumes"))
+		})
+		It("Should print the command path when --help is given", func() {
+			cmdLine := []string{"volume", "--help"}
+			_, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			help := readHelp()
+			Expect(help).To(ContainSubstring("Usage: my-cli volume [OPTIONS]"))
+			Expect(help).To(ContainSubstring("Create a volume"))
+		})
+	})
 	Describe("ArgParser.GetArgs()", func() {
 		It("Should return all un-matched arguments and options", func() {
 			parser := args.NewParser()
@@ -0,0 +1,162 @@
+package args
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Enable git style plugin commands. When no command matches, RunCommand() looks for an
+// executable named prefix + command on the PATH and runs it with the remaining arguments.
+// The resolved options of the parser are passed to the plugin as environment variables named
+// by Env() or derived from the EnvPrefix. Without an EnvPrefix, options that have no Env()
+// are only passed if they were given on the command line.
+//
+//	// `mycli deploy --fast` runs `mycli-deploy --fast`
+//	parser := args.NewParser(args.EnablePlugins("mycli-"))
+func EnablePlugins(prefix string) ParseModifier {
+	return func(parser *ArgParser) {
+		parser.pluginPrefix = prefix
+	}
+}
+
+// Run the plugin named by the first non option argument. Returns false if plugins are
+// not enabled or no plugin by that name was found on the PATH
+func (self *ArgParser) runPlugin() (int, bool, error) {
+	if self.pluginPrefix == "" {
+		return 0, false, nil
+	}
+
+	args := self.GetArgs()
+	for idx := 0; idx < len(args); idx++ {
+		arg := args[idx]
+		if strings.HasPrefix(arg, "-") {
+			// Skip the value of an option we know expects one, so it is not mistaken for the plugin name
+			if rule := self.matchAlias(arg, IsOption); rule != nil && rule.Action == nil {
+				idx++
+			}
+			continue
+		}
+		path, err := exec.LookPath(self.pluginPrefix + arg)
+		if err != nil {
+			return 0, false, nil
+		}
+
+		cmd := exec.CommandContext(self.Context(), path, append(args[:idx:idx], args[idx+1:]...)...)
+		cmd.Stdin = self.InputIO
+		cmd.Stdout = self.HelpIO
+		cmd.Stderr = self.ErrorIO
+		cmd.Env = append(os.Environ(), self.pluginEnv()...)
+
+		if err := cmd.Run(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				return exitErr.ExitCode(), true, nil
+			}
+			return 1, true, errors.Wrapf(err, "while running plugin '%s'", path)
+		}
+		return 0, true, nil
+	}
+	return 0, false, nil
+}
+
+// Returns the resolved values of our options and config as environment variables
+func (self *ArgParser) pluginEnv() []string {
+	var results []string
+	opts := self.GetOpts()
+	if opts == nil {
+		return nil
+	}
+
+	for _, rule := range self.rules {
+		// Options the parser added for itself, such as '--no-color', are not meant for the plugin
+		if rule.HasFlag(IsCommand|IsConfigGroup) || self.isBuiltin(rule) {
+			continue
+		}
+		// Without an env prefix the derived name could clobber an unrelated variable such
+		// as 'ENDPOINT', so only pass those rules if the user gave them on the command line
+		if len(rule.envVars()) == 0 && rule.EnvPrefix == "" && !rule.HasFlag(Seen) {
+			continue
+		}
+		value := envString(opts.Group(rule.Group).Get(rule.Name))
+		if value == "" {
+			continue
+		}
+		results = append(results, fmt.Sprintf("%s=%s", rule.envName(), value))
+	}
+	return results
+}
+
+// Returns the first environment variable the rule reads, or a name derived from the
+// env prefix, group and name of the rule. IE: 'MYCLI_DATABASE_HOST'
+func (self *Rule) envName() string {
+	if envs := self.envVars(); len(envs) != 0 {
+		return envs[0]
+	}
+	return self.autoEnvName()
+}
+
+// Format a resolved option value the way the env parsing of a rule expects
+func envString(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(value, ",")
+	case map[string]string:
+		var pairs []string
+		for key, item := range value {
+			pairs = append(pairs, key+"="+item)
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// Returns rules describing the plugins found on the PATH that do not collide with our commands
+func (self *ArgParser) pluginRules() Rules {
+	if self.pluginPrefix == "" {
+		return nil
+	}
+
+	found := make(map[string]string)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			name := strings.TrimPrefix(file.Name(), self.pluginPrefix)
+			if name == file.Name() || name == "" || file.IsDir() || file.Mode()&0111 == 0 {
+				continue
+			}
+			// The first plugin on the PATH wins, the same as exec.LookPath()
+			if _, exists := found[name]; !exists && self.matchAlias(name, IsCommand) == nil {
+				found[name] = file.Name()
+			}
+		}
+	}
+
+	var names []string
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var results Rules
+	for _, name := range names {
+		rule := newRule()
+		rule.SetFlag(IsCommand)
+		rule.Name = name
+		rule.Aliases = []string{name}
+		rule.RuleDesc = fmt.Sprintf("Plugin provided by '%s'", found[name])
+		results = append(results, rule)
+	}
+	return results
+}
@@ -0,0 +1,97 @@
+package args_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+)
+
+var _ = Describe("Plugins", func() {
+	var parser *args.ArgParser
+	var dir, path string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "args-plugins")
+		Expect(err).To(BeNil())
+
+		script := "#!/bin/sh\necho \"$@ $MYCLI_ENDPOINT $MYCLI_DEBUG\" > " + filepath.Join(dir, "output") + "\nexit 3\n"
+		Expect(ioutil.WriteFile(filepath.Join(dir, "mycli-deploy"), []byte(script), 0755)).To(Succeed())
+
+		path = os.Getenv("PATH")
+		os.Setenv("PATH", dir)
+
+		parser = args.NewParser(args.Name("mycli"), args.EnvPrefix("MYCLI_"), args.EnablePlugins("mycli-"))
+		parser.AddOption("--endpoint").Env("ENDPOINT").Default("http://localhost")
+		parser.AddOption("--debug").IsTrue()
+		parser.AddCommand("status", func(subParser *args.ArgParser, data interface{}) (int, error) {
+			return 0, nil
+		})
+	})
+
+	AfterEach(func() {
+		os.Setenv("PATH", path)
+		os.RemoveAll(dir)
+	})
+
+	Describe("ArgParser.RunCommand()", func() {
+		It("Should run the plugin with the remaining args and resolved options", func() {
+			cmdLine := []string{"--debug", "deploy", "--fast", "now"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(3))
+
+			output, err := ioutil.ReadFile(filepath.Join(dir, "output"))
+			Expect(err).To(BeNil())
+			Expect(string(output)).To(Equal("--fast now http://localhost true\n"))
+		})
+		It("Should not mistake the value of an option for the plugin name", func() {
+			// Options following the terminator are not parsed and are left for the plugin
+			Expect(ioutil.WriteFile(filepath.Join(dir, "mycli-localhost"), []byte("#!/bin/sh\nexit 4\n"), 0755)).To(Succeed())
+
+			cmdLine := []string{"--", "--endpoint", "localhost", "deploy"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(3))
+
+			output, err := ioutil.ReadFile(filepath.Join(dir, "output"))
+			Expect(err).To(BeNil())
+			Expect(string(output)).To(Equal("-- --endpoint localhost http://localhost false\n"))
+		})
+	})
+
+	Describe("ArgParser.RunCommand() environment", func() {
+		It("Should not pass options added by the parser or unprefixed defaults to the plugin", func() {
+			script := "#!/bin/sh\necho \"${NO_COLOR-unset} ${VERSION-unset} ${ENDPOINT-unset} ${DEBUG-unset}\" > " +
+				filepath.Join(dir, "output") + "\n"
+			Expect(ioutil.WriteFile(filepath.Join(dir, "mycli-deploy"), []byte(script), 0755)).To(Succeed())
+			if value, ok := os.LookupEnv("NO_COLOR"); ok {
+				os.Unsetenv("NO_COLOR")
+				defer os.Setenv("NO_COLOR", value)
+			}
+
+			parser := args.NewParser(args.Name("mycli"), args.Version("1.0.0"), args.EnablePlugins("mycli-"))
+			parser.AddOption("--endpoint").Default("http://localhost")
+			parser.AddOption("--debug").IsTrue()
+			cmdLine := []string{"--debug", "deploy"}
+			_, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+
+			output, err := ioutil.ReadFile(filepath.Join(dir, "output"))
+			Expect(err).To(BeNil())
+			Expect(string(output)).To(Equal("unset unset unset true\n"))
+		})
+	})
+
+	Describe("ArgParser.GenerateHelp()", func() {
+		It("Should list plugins found on the PATH with the commands", func() {
+			Expect(parser.GenerateHelp()).To(ContainSubstring("Commands:\n" +
+				"  status   \n" +
+				"  deploy   Plugin provided by 'mycli-deploy'\n"))
+		})
+	})
+})
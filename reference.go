@@ -0,0 +1,235 @@
+package args
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var regexAnchor = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Writes a reference document in a markup language
+type referenceWriter interface {
+	Heading(level int, title, anchor string)
+	Paragraph(text string)
+	Code(text string)
+	Table(headers []string, rows [][]string)
+	Link(text, anchor string) string
+	Literal(text string) string
+}
+
+// Generate a markdown reference of the parser and all sub commands defined with
+// AddCommands(). Each command has an anchor derived from the command path
+// (IE: 'my-cli volume create' = '#my-cli-volume-create') which does not change
+// when the document is regenerated.
+func (self *ArgParser) GenerateMarkdown(w io.Writer) error {
+	doc := &markdownWriter{}
+	self.writeReference(doc, 1)
+	_, err := w.Write(doc.Bytes())
+	return err
+}
+
+// Generate a reStructuredText reference of the parser and all sub commands defined
+// with AddCommands(). Each command has a label derived from the command path
+// (IE: 'my-cli volume create' = 'my-cli-volume-create')
+func (self *ArgParser) GenerateRST(w io.Writer) error {
+	doc := &rstWriter{}
+	self.writeReference(doc, 1)
+	_, err := w.Write(doc.Bytes())
+	return err
+}
+
+func (self *ArgParser) writeReference(doc referenceWriter, level int) {
+	name := self.manName()
+	doc.Heading(level, name, referenceAnchor(name))
+	if self.Description != "" {
+		doc.Paragraph(self.Description)
+	}
+
+	usage := strings.TrimSpace(self.GenerateUsage(IsOption) + " " + self.GenerateUsage(IsArgument))
+	commands := self.helpRules(IsCommand)
+	if len(commands) != 0 {
+		usage += " <command>"
+	}
+	doc.Code(name + " " + usage)
+
+	if len(commands) != 0 {
+		var rows [][]string
+		for _, rule := range commands {
+			cmdName := doc.Literal(strings.Join(rule.Aliases, ", "))
+			if rule.CommandDef != nil {
+				cmdName = doc.Link(cmdName, referenceAnchor(self.commandPath(rule)))
+			}
+			rows = append(rows, []string{cmdName, rule.RuleDesc})
+		}
+		doc.Heading(level+1, "Commands", "")
+		doc.Table([]string{"Command", "Description"}, rows)
+	}
+
+	arguments := self.helpRules(IsArgument)
+	if len(arguments) != 0 {
+		var rows [][]string
+		for _, rule := range arguments {
+			required := "no"
+			if rule.HasFlag(IsRequired) {
+				required = "yes"
+			}
+			rows = append(rows, []string{doc.Literal(rule.Name), required,
+				doc.Literal(rule.defaultString()), rule.RuleDesc})
+		}
+		doc.Heading(level+1, "Arguments", "")
+		doc.Table([]string{"Argument", "Required", "Default", "Description"}, rows)
+	}
+
+	options := append(self.helpRules(IsOption), self.globalRules()...)
+	if len(options) != 0 {
+		var rows [][]string
+		for _, rule := range options {
+			rows = append(rows, []string{doc.Literal(strings.Join(rule.Aliases, ", ")),
+				doc.Literal(rule.defaultString()), doc.Literal(strings.Join(rule.envVars(), ", ")),
+				doc.Literal(rule.BackendKey("")), rule.RuleDesc})
+		}
+		doc.Heading(level+1, "Options", "")
+		doc.Table([]string{"Option", "Default", "Environment", "Backend Key", "Description"}, rows)
+	}
+
+	configs := self.helpRules(IsConfig | IsConfigGroup)
+	if len(configs) != 0 {
+		var rows [][]string
+		for _, rule := range configs {
+			key := rule.Name
+			if rule.HasFlag(IsConfigGroup) {
+				key = rule.Group + ".*"
+			}
+			rows = append(rows, []string{doc.Literal(key), doc.Literal(rule.Group),
+				doc.Literal(rule.defaultString()), doc.Literal(strings.Join(rule.envVars(), ", ")),
+				doc.Literal(rule.BackendKey("")), rule.RuleDesc})
+		}
+		doc.Heading(level+1, "Configuration", "")
+		doc.Table([]string{"Key", "Group", "Default", "Environment", "Backend Key", "Description"}, rows)
+	}
+
+	if len(self.examples) != 0 {
+		doc.Heading(level+1, "Examples", "")
+		for _, example := range self.examples {
+			if example.Desc != "" {
+				doc.Paragraph(example.Desc)
+			}
+			doc.Code(self.exampleCmdLine(example))
+		}
+	}
+
+	for _, rule := range commands {
+		if parser := self.commandDefParser(rule); parser != nil {
+			parser.writeReference(doc, level+1)
+		}
+	}
+}
+
+// Returns a stable anchor for the command path. IE: 'my-cli volume' = 'my-cli-volume'
+func referenceAnchor(path string) string {
+	return strings.Trim(regexAnchor.ReplaceAllString(strings.ToLower(path), "-"), "-")
+}
+
+// Returns the default value of the rule or an empty string if the rule has no default
+func (self *Rule) defaultString() string {
+	if self.Default == nil {
+		return ""
+	}
+	return *self.Default
+}
+
+type markdownWriter struct {
+	bytes.Buffer
+}
+
+func (self *markdownWriter) Heading(level int, title, anchor string) {
+	if anchor != "" {
+		fmt.Fprintf(self, "<a name=\"%s\"></a>\n", anchor)
+	}
+	fmt.Fprintf(self, "%s %s\n\n", strings.Repeat("#", level), title)
+}
+
+func (self *markdownWriter) Paragraph(text string) {
+	self.WriteString(text + "\n\n")
+}
+
+func (self *markdownWriter) Code(text string) {
+	self.WriteString("```\n" + text + "\n```\n\n")
+}
+
+func (self *markdownWriter) Table(headers []string, rows [][]string) {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+	self.WriteString("| " + strings.Join(headers, " | ") + " |\n")
+	self.WriteString(strings.Repeat("|---", len(headers)) + "|\n")
+	for _, row := range rows {
+		for idx := range row {
+			row[idx] = escape.Replace(row[idx])
+		}
+		self.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+	self.WriteString("\n")
+}
+
+func (self *markdownWriter) Link(text, anchor string) string {
+	return fmt.Sprintf("[%s](#%s)", text, anchor)
+}
+
+func (self *markdownWriter) Literal(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "`" + text + "`"
+}
+
+type rstWriter struct {
+	bytes.Buffer
+}
+
+var rstUnderlines = []string{"=", "-", "~", "^", "\""}
+
+func (self *rstWriter) Heading(level int, title, anchor string) {
+	if anchor != "" {
+		fmt.Fprintf(self, ".. _%s:\n\n", anchor)
+	}
+	underline := rstUnderlines[len(rstUnderlines)-1]
+	if level <= len(rstUnderlines) {
+		underline = rstUnderlines[level-1]
+	}
+	fmt.Fprintf(self, "%s\n%s\n\n", title, strings.Repeat(underline, len(title)))
+}
+
+func (self *rstWriter) Paragraph(text string) {
+	self.WriteString(text + "\n\n")
+}
+
+func (self *rstWriter) Code(text string) {
+	self.WriteString("::\n\n    " + strings.Replace(text, "\n", "\n    ", -1) + "\n\n")
+}
+
+func (self *rstWriter) Table(headers []string, rows [][]string) {
+	self.WriteString(".. list-table::\n   :header-rows: 1\n\n")
+	for _, row := range append([][]string{headers}, rows...) {
+		for idx, cell := range row {
+			prefix := "     - "
+			if idx == 0 {
+				prefix = "   * - "
+			}
+			self.WriteString(prefix + strings.Replace(cell, "\n", " ", -1) + "\n")
+		}
+	}
+	self.WriteString("\n")
+}
+
+func (self *rstWriter) Link(text, anchor string) string {
+	return fmt.Sprintf(":ref:`%s <%s>`", strings.Trim(text, "`"), anchor)
+}
+
+func (self *rstWriter) Literal(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "``" + text + "``"
+}
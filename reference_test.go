@@ -0,0 +1,66 @@
+package args_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+)
+
+var _ = Describe("Reference", func() {
+	var parser *args.ArgParser
+
+	BeforeEach(func() {
+		parser = args.NewParser(args.Name("my-cli"), args.Desc("Manage volumes"))
+		parser.AddOption("--endpoint").Alias("-e").Env("API_ENDPOINT").
+			Default("http://localhost").Help("The api endpoint")
+		parser.AddConfig("token").Help("The api token | secret")
+		parser.AddConfigGroup("database").Help("Database connection settings")
+		parser.AddCommand("debug", nil).Help("Debug the cli")
+		parser.AddCommands(args.Command{
+			Name: "volume",
+			Help: "Manage a volume",
+			Subcommands: []args.Command{
+				{
+					Name: "create",
+					Help: "Create a volume",
+					Options: func(subParser *args.ArgParser) {
+						subParser.AddArgument("name").Required().Help("The name of the volume")
+					},
+				},
+			},
+		})
+	})
+
+	Describe("ArgParser.GenerateMarkdown()", func() {
+		It("Should generate a reference of the command tree", func() {
+			var buf bytes.Buffer
+			Expect(parser.GenerateMarkdown(&buf)).To(Succeed())
+			doc := buf.String()
+
+			Expect(doc).To(ContainSubstring("<a name=\"my-cli\"></a>\n# my-cli\n\nManage volumes\n"))
+			Expect(doc).To(ContainSubstring("| `debug` | Debug the cli |"))
+			Expect(doc).To(ContainSubstring("| [`volume`](#my-cli-volume) | Manage a volume |"))
+			Expect(doc).To(ContainSubstring(
+				"| `--endpoint, -e` | `http://localhost` | `API_ENDPOINT` | `/endpoint` | The api endpoint |"))
+			Expect(doc).To(ContainSubstring("| `token` |  |  |  | `/token` | The api token \\| secret |"))
+			Expect(doc).To(ContainSubstring("| `database.*` | `database` |  |  | `/database` |"))
+			Expect(doc).To(ContainSubstring("<a name=\"my-cli-volume\"></a>\n## my-cli volume\n"))
+			Expect(doc).To(ContainSubstring("<a name=\"my-cli-volume-create\"></a>\n### my-cli volume create\n"))
+			Expect(doc).To(ContainSubstring("| `name` | yes |  | The name of the volume |"))
+		})
+	})
+
+	Describe("ArgParser.GenerateRST()", func() {
+		It("Should generate a reference with labels for each command", func() {
+			var buf bytes.Buffer
+			Expect(parser.GenerateRST(&buf)).To(Succeed())
+			doc := buf.String()
+
+			Expect(doc).To(ContainSubstring(".. _my-cli:\n\nmy-cli\n======\n"))
+			Expect(doc).To(ContainSubstring("   * - :ref:`volume <my-cli-volume>`\n     - Manage a volume\n"))
+			Expect(doc).To(ContainSubstring(".. _my-cli-volume-create:\n\nmy-cli volume create\n~~~~~~~~~~~~~~~~~~~~\n"))
+		})
+	})
+})
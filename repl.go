@@ -0,0 +1,218 @@
+package args
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// Set the prompt RunREPL() displays before reading each line, defaults to '<name>> '
+func Prompt(prompt string) ParseModifier {
+	return func(parser *ArgParser) {
+		parser.prompt = prompt
+	}
+}
+
+// Set the file RunREPL() loads the line history from and appends each line to
+func HistoryFile(fileName string) ParseModifier {
+	return func(parser *ArgParser) {
+		parser.historyFile = fileName
+	}
+}
+
+// Read lines from 'in' and dispatch each line through the command tree as if it was given
+// on the command line. Errors are reported to 'out' without ending the session. The 'data'
+// is passed to every command such that commands can share state across lines.
+//
+// Built in lines are 'history', 'exit' and 'quit'. A line ending with '?' lists the
+// completion candidates for the line. RunREPL() returns when the context is cancelled
+// or 'in' reaches EOF.
+//
+//	parser := args.NewParser(args.Name("mycli"), args.HistoryFile("/home/user/.mycli_history"))
+//	err := parser.RunREPL(context.Background(), parser.InputIO, parser.HelpIO, state)
+func (self *ArgParser) RunREPL(ctx context.Context, in io.Reader, out io.Writer, data interface{}) error {
+	history, err := self.loadHistory()
+	if err != nil {
+		return err
+	}
+
+	prompt := self.prompt
+	if prompt == "" {
+		prompt = self.Name + "> "
+	}
+
+	// Help messages and errors should be displayed in the session
+	defer func(helpIO, errorIO io.Writer) {
+		self.HelpIO, self.ErrorIO = helpIO, errorIO
+	}(self.HelpIO, self.ErrorIO)
+	self.HelpIO, self.ErrorIO = out, out
+
+	// Remember the initial state of our rules so each line is parsed by a fresh parser
+	initial := self.saveRules()
+
+	// Stop the reader when we return, else it blocks forever trying to send the next line
+	readCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines := make(chan string)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-readCtx.Done():
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	for {
+		fmt.Fprint(out, prompt)
+
+		var line string
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-readErr:
+			fmt.Fprintln(out)
+			return err
+		case line = <-lines:
+		}
+
+		line = strings.TrimSpace(line)
+		switch line {
+		case "":
+			continue
+		case "exit", "quit":
+			return nil
+		case "history":
+			for idx, item := range history {
+				fmt.Fprintf(out, "%5d  %s\n", idx+1, item)
+			}
+			continue
+		}
+
+		history = append(history, line)
+		if err := self.saveHistory(line); err != nil {
+			fmt.Fprintf(out, "error: %s\n", err)
+		}
+
+		complete := strings.HasSuffix(line, "?")
+		words, err := splitLine(strings.TrimSuffix(line, "?"))
+		if err != nil {
+			fmt.Fprintf(out, "error: %s\n", err)
+			continue
+		}
+
+		if complete {
+			// If the user asked for candidates after a space, we are completing a new word
+			if len(words) == 0 || strings.HasSuffix(line, " ?") {
+				words = append(words, "")
+			}
+			for _, candidate := range self.Complete(words) {
+				fmt.Fprintln(out, candidate)
+			}
+			continue
+		}
+
+		// Reset the parser and our rules to the state before the first line was parsed
+		initial.restore()
+		self.Command, self.err, self.ctx = nil, nil, ctx
+
+		if _, err := self.ParseAndRun(&words, data); err != nil {
+			fmt.Fprintf(out, "error: %s\n", err)
+		}
+	}
+}
+
+// Adds a 'shell' command such that `my-cli shell` runs RunREPL() on InputIO and HelpIO
+func (self *ArgParser) AddShellCommand() *RuleModifier {
+	return self.AddCommand("shell", func(subParser *ArgParser, data interface{}) (int, error) {
+		if err := self.RunREPL(subParser.Context(), subParser.InputIO, subParser.HelpIO, data); err != nil {
+			return 1, err
+		}
+		return 0, nil
+	}).Help("Start an interactive shell")
+}
+
+func (self *ArgParser) loadHistory() ([]string, error) {
+	if self.historyFile == "" {
+		return nil, nil
+	}
+	content, err := ioutil.ReadFile(self.historyFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "while reading history file '%s'", self.historyFile)
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n"), nil
+}
+
+func (self *ArgParser) saveHistory(line string) error {
+	if self.historyFile == "" {
+		return nil
+	}
+	file, err := os.OpenFile(self.historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "while opening history file '%s'", self.historyFile)
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintln(file, line); err != nil {
+		return errors.Wrapf(err, "while writing history file '%s'", self.historyFile)
+	}
+	return nil
+}
+
+// Split a line into words the way a shell would, honoring single quotes,
+// double quotes and backslash escapes
+func splitLine(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	var quote rune
+	var inWord, escaped bool
+
+	for _, char := range line {
+		switch {
+		case escaped:
+			word.WriteRune(char)
+			escaped = false
+		case char == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			} else {
+				word.WriteRune(char)
+			}
+		case char == '\'' || char == '"':
+			quote, inWord = char, true
+		case char == ' ' || char == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(char)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.Errorf("unterminated quote %c", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,102 @@
+package args_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("REPL", func() {
+	var parser *args.ArgParser
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "args-repl")
+		Expect(err).To(BeNil())
+
+		parser = args.NewParser(args.Name("mycli"), args.Prompt("$ "),
+			args.HistoryFile(filepath.Join(dir, "history")))
+		parser.AddOption("--verbose").Alias("-v").IsTrue()
+		parser.AddCommand("add", func(subParser *args.ArgParser, data interface{}) (int, error) {
+			subParser.AddArgument("name").Required()
+			opts, err := subParser.Parse(nil)
+			if err != nil {
+				return 1, err
+			}
+			items := data.(*[]string)
+			name := opts.String("name")
+			if opts.Bool("verbose") {
+				name += " (verbose)"
+			}
+			*items = append(*items, name)
+			return 0, nil
+		})
+		parser.AddCommand("list", nil)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Describe("ArgParser.RunREPL()", func() {
+		It("Should dispatch each line through the command tree", func() {
+			var items []string
+			var out bytes.Buffer
+			in := strings.NewReader("-v add 'first item'\nadd second\nadd\nexit\nadd never\n")
+
+			Expect(parser.RunREPL(context.Background(), in, &out, &items)).To(Succeed())
+			Expect(items).To(Equal([]string{"first item (verbose)", "second"}))
+			Expect(out.String()).To(Equal("$ $ $ error: argument 'name' is required\n$ "))
+		})
+		It("Should list completion candidates for lines ending in '?'", func() {
+			var out bytes.Buffer
+			in := strings.NewReader("l?\n--v?\n")
+
+			Expect(parser.RunREPL(context.Background(), in, &out, nil)).To(Succeed())
+			Expect(out.String()).To(Equal("$ list\n$ --verbose\n$ \n"))
+		})
+		It("Should persist the line history", func() {
+			var out bytes.Buffer
+			in := strings.NewReader("add one\nadd two\n")
+			Expect(parser.RunREPL(context.Background(), in, &out, &[]string{})).To(Succeed())
+
+			out.Reset()
+			in = strings.NewReader("history\n")
+			Expect(parser.RunREPL(context.Background(), in, &out, nil)).To(Succeed())
+			Expect(out.String()).To(Equal("$     1  add one\n    2  add two\n$ \n"))
+		})
+		It("Should stop when the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			reader, writer, _ := os.Pipe()
+			defer writer.Close()
+			defer reader.Close()
+
+			Expect(parser.RunREPL(ctx, reader, ioutil.Discard, nil)).To(Succeed())
+		})
+	})
+	Describe("ArgParser.AddShellCommand()", func() {
+		It("Should run the REPL on InputIO and HelpIO", func() {
+			var items []string
+			var out bytes.Buffer
+			parser.AddShellCommand()
+			parser.InputIO = strings.NewReader("add one\nexit\n")
+			parser.HelpIO = &out
+
+			cmdLine := []string{"shell"}
+			retCode, err := parser.ParseAndRun(&cmdLine, &items)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(0))
+			Expect(items).To(Equal([]string{"one"}))
+			Expect(out.String()).To(Equal("$ $ "))
+		})
+	})
+})
@@ -20,7 +20,7 @@ func (self *RuleModifier) GetRule() *Rule {
 }
 
 func (self *RuleModifier) IsString() *RuleModifier {
-	self.rule.Cast = castString
+	self.rule.setCast(castString, "string")
 	return self
 }
 
@@ -30,12 +30,12 @@ func (self *RuleModifier) IsTrue() *RuleModifier {
 		rule.Value = true
 		return nil
 	}
-	self.rule.Cast = castBool
+	self.rule.setCast(castBool, "bool")
 	return self
 }
 
 func (self *RuleModifier) IsBool() *RuleModifier {
-	self.rule.Cast = castBool
+	self.rule.setCast(castBool, "bool")
 	self.rule.Value = false
 	return self
 }
@@ -47,15 +47,22 @@ func (self *RuleModifier) Default(value string) *RuleModifier {
 
 func (self *RuleModifier) StoreInt(dest *int) *RuleModifier {
 	// Implies IsInt()
-	self.rule.Cast = castInt
+	self.rule.setCast(castInt, "int")
 	self.rule.StoreValue = func(value interface{}) {
 		*dest = value.(int)
 	}
 	return self
 }
 
+// The value is a path to a file, shell completion will complete file names for this rule
+func (self *RuleModifier) IsFilePath() *RuleModifier {
+	self.rule.setCast(castString, "string")
+	self.rule.SetFlag(IsFilePath)
+	return self
+}
+
 func (self *RuleModifier) IsInt() *RuleModifier {
-	self.rule.Cast = castInt
+	self.rule.setCast(castInt, "int")
 	return self
 }
 
@@ -64,7 +71,7 @@ func (self *RuleModifier) StoreTrue(dest *bool) *RuleModifier {
 		rule.Value = true
 		return nil
 	}
-	self.rule.Cast = castBool
+	self.rule.setCast(castBool, "bool")
 	self.rule.StoreValue = func(value interface{}) {
 		*dest = value.(bool)
 	}
@@ -72,13 +79,13 @@ func (self *RuleModifier) StoreTrue(dest *bool) *RuleModifier {
 }
 
 func (self *RuleModifier) IsStringSlice() *RuleModifier {
-	self.rule.Cast = castStringSlice
+	self.rule.setCast(castStringSlice, "list")
 	self.rule.SetFlag(IsGreedy)
 	return self
 }
 
 func (self *RuleModifier) IsStringMap() *RuleModifier {
-	self.rule.Cast = castStringMap
+	self.rule.setCast(castStringMap, "map")
 	self.rule.SetFlag(IsGreedy)
 	return self
 }
@@ -86,7 +93,7 @@ func (self *RuleModifier) IsStringMap() *RuleModifier {
 // TODO: Make this less horribad, and use more reflection to make the interface simpler
 // It should also take more than just []string but also []int... etc...
 func (self *RuleModifier) StoreStringSlice(dest *[]string) *RuleModifier {
-	self.rule.Cast = castStringSlice
+	self.rule.setCast(castStringSlice, "list")
 	self.rule.StoreValue = func(src interface{}) {
 		// First clear the current slice if any
 		*dest = nil
@@ -103,7 +110,7 @@ func (self *RuleModifier) StoreStringSlice(dest *[]string) *RuleModifier {
 }
 
 func (self *RuleModifier) StoreStringMap(dest *map[string]string) *RuleModifier {
-	self.rule.Cast = castStringMap
+	self.rule.setCast(castStringMap, "map")
 	self.rule.StoreValue = func(src interface{}) {
 		// clear the current before assignment
 		*dest = nil
@@ -112,12 +119,20 @@ func (self *RuleModifier) StoreStringMap(dest *map[string]string) *RuleModifier
 	return self
 }
 
-// Indicates this option has an alias it can go by
+// Indicates this option or command has an alias it can go by
+//	parser.AddCommand("list", listVolumes).Alias("ls")
 func (self *RuleModifier) Alias(aliasName string) *RuleModifier {
 	self.rule.Aliases = append(self.rule.Aliases, aliasName)
 	return self
 }
 
+// Hides this command or option from the generated help message; the
+// rule will still match if the user provides it on the command line
+func (self *RuleModifier) Hidden() *RuleModifier {
+	self.rule.SetFlag(IsHidden)
+	return self
+}
+
 // Makes this option or positional argument required
 func (self *RuleModifier) Required() *RuleModifier {
 	self.rule.SetFlag(IsRequired)
@@ -137,7 +152,7 @@ func (self *RuleModifier) StoreStr(dest *string) *RuleModifier {
 
 func (self *RuleModifier) StoreString(dest *string) *RuleModifier {
 	// Implies IsString()
-	self.rule.Cast = castString
+	self.rule.setCast(castString, "string")
 	self.rule.StoreValue = func(value interface{}) {
 		*dest = value.(string)
 	}
@@ -150,7 +165,7 @@ func (self *RuleModifier) Count() *RuleModifier {
 		rule.Count = rule.Count + 1
 		return nil
 	}
-	self.rule.Cast = castInt
+	self.rule.setCast(castInt, "int")
 	return self
 }
 
@@ -159,11 +174,49 @@ func (self *RuleModifier) Env(varName string) *RuleModifier {
 	return self
 }
 
+// Do not read the environment variable derived from the rule name when the parser was created with AutoEnv()
+func (self *RuleModifier) NoAutoEnv() *RuleModifier {
+	self.rule.autoEnv = false
+	return self
+}
+
 func (self *RuleModifier) Help(message string) *RuleModifier {
 	self.rule.RuleDesc = message
 	return self
 }
 
+// Provide shell completion candidates for the value of this rule at run time
+//	parser.AddArgument("volume").Completer(func(prefix string) []string {
+//		return api.ListVolumeNames()
+//	})
+func (self *RuleModifier) Completer(completer CompleterFunc) *RuleModifier {
+	self.rule.Completer = completer
+	return self
+}
+
+// Marks this option as accepted by the parser and all of its sub commands. The option
+// resolves to a single value regardless of where it appears on the command line and is
+// listed in the 'Global Options' section of the sub command help
+//	parser.AddOption("--verbose").Alias("-v").Count().Persistent()
+func (self *RuleModifier) Persistent() *RuleModifier {
+	self.rule.SetFlag(IsPersistent)
+	return self
+}
+
+// Adds an example to the 'Examples:' section of the help message of the command
+//	parser.AddCommand("create", createVolume).Example("--size 10 my-volume", "Create a 10GB volume")
+func (self *RuleModifier) Example(cmdLine, desc string) *RuleModifier {
+	self.rule.Examples = append(self.rule.Examples, Example{CmdLine: cmdLine, Desc: desc})
+	return self
+}
+
+// Lists this command under a titled section of the same category in the help message
+//	parser.AddCommand("create", createVolume).InCategory("Volume Management")
+func (self *RuleModifier) InCategory(category string) *RuleModifier {
+	self.rule.Category = category
+	return self
+}
+
 func (self *RuleModifier) InGroup(group string) *RuleModifier {
 	self.rule.Group = group
 	return self
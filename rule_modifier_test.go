@@ -413,4 +413,113 @@ var _ = Describe("RuleModifier", func() {
 			Expect(value).To(Equal(1))
 		})
 	})
+	Describe("RuleModifier.Alias()", func() {
+		It("Should run a command when called by an alias", func() {
+			parser := args.NewParser()
+			called := 0
+			parser.AddCommand("list", func(parent *args.ArgParser, data interface{}) (int, error) {
+				called++
+				return 0, nil
+			}).Alias("ls")
+
+			cmdLine := []string{"ls"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(0))
+			Expect(called).To(Equal(1))
+		})
+		It("Should list command aliases after the command name in help", func() {
+			parser := args.NewParser()
+			parser.AddCommand("list", nil).Alias("ls").Help("List things")
+			parser.AddCommand("delete", nil).Alias("rm").Help("Delete things")
+
+			msg := parser.GenerateHelpSection(args.IsCommand)
+			Expect(msg).To(Equal("  list, ls     List things\n  delete, rm   Delete things\n"))
+		})
+	})
+	Describe("RuleModifier.Hidden()", func() {
+		It("Should not display hidden commands in help", func() {
+			parser := args.NewParser()
+			parser.AddCommand("list", nil).Help("List things")
+			parser.AddCommand("debug-dump", nil).Hidden().Help("Internal only")
+
+			msg := parser.GenerateHelp()
+			Expect(msg).To(ContainSubstring("list"))
+			Expect(msg).ToNot(ContainSubstring("debug-dump"))
+		})
+		It("Should still run a hidden command", func() {
+			parser := args.NewParser()
+			called := 0
+			parser.AddCommand("debug-dump", func(parent *args.ArgParser, data interface{}) (int, error) {
+				called++
+				return 0, nil
+			}).Hidden()
+
+			cmdLine := []string{"debug-dump"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(0))
+			Expect(called).To(Equal(1))
+		})
+	})
+	Describe("RuleModifier.InCategory()", func() {
+		It("Should list commands under a section for each category", func() {
+			parser := args.NewParser()
+			parser.AddCommand("version", nil).Help("Print the version")
+			parser.AddCommand("create", nil).InCategory("Volume Management").Help("Create a volume")
+			parser.AddCommand("attach", nil).InCategory("Host Management").Help("Attach a host")
+			parser.AddCommand("delete", nil).InCategory("Volume Management").Help("Delete a volume")
+
+			msg := parser.GenerateHelp()
+			Expect(msg).To(ContainSubstring("\nVolume Management:\n" +
+				"  create    Create a volume\n" +
+				"  delete    Delete a volume\n" +
+				"\nHost Management:\n" +
+				"  attach    Attach a host\n" +
+				"\nCommands:\n" +
+				"  version   Print the version\n"))
+		})
+	})
+	Describe("RuleModifier.Persistent()", func() {
+		var parser *args.ArgParser
+		var opts *args.Options
+		var help string
+
+		BeforeEach(func() {
+			parser = args.NewParser(args.Name("my-cli"))
+			parser.AddOption("--verbose").Alias("-v").Count().Persistent().Help("Be verbose")
+			parser.AddOption("--endpoint").Help("The endpoint")
+			parser.AddCommand("volume", func(subParser *args.ArgParser, data interface{}) (int, error) {
+				subParser.AddCommand("create", func(subParser *args.ArgParser, data interface{}) (int, error) {
+					subParser.AddOption("--size").Help("Size of the volume")
+					help = subParser.GenerateHelp()
+					opts = subParser.ParseSimple(nil)
+					return 0, nil
+				})
+				return subParser.ParseAndRun(nil, data)
+			})
+		})
+
+		It("Should resolve to one value regardless of position", func() {
+			cmdLine := []string{"-v", "volume", "create", "-v"}
+			_, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(opts.Int("verbose")).To(Equal(2))
+		})
+		It("Should accept the option after the command when StopParsingOnCommand is set", func() {
+			parser.StopParsingOnCommand = true
+			cmdLine := []string{"volume", "create", "--verbose"}
+			_, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(opts.Int("verbose")).To(Equal(1))
+		})
+		It("Should list persistent options in the Global Options section of sub commands", func() {
+			cmdLine := []string{"volume", "create"}
+			_, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(help).To(ContainSubstring("Options:\n  --size   Size of the volume\n"))
+			Expect(help).To(ContainSubstring("Global Options:\n  -v, --verbose   Be verbose\n"))
+			Expect(help).ToNot(ContainSubstring("--endpoint"))
+		})
+	})
 })
@@ -32,6 +32,9 @@ const (
 	DefaultValue
 	EnvValue
 	Seen
+	IsHidden
+	IsPersistent
+	IsFilePath
 )
 
 type Rule struct {
@@ -49,16 +52,29 @@ type Rule struct {
 	Action      ActionFunc
 	StoreValue  StoreFunc
 	CommandFunc CommandFunc
+	CommandDef  *Command
+	Examples    []Example
+	Completer   CompleterFunc
+	Category    string
 	Group       string
 	Key         string
 	NotGreedy   bool
 	Flags       int64
+	lookupEnv   func(string) (string, bool)
+	autoEnv     bool
+	castType    string
 }
 
 func newRule() *Rule {
 	return &Rule{Cast: castString, Group: DefaultOptionGroup}
 }
 
+// Set the function used to cast values and record the name of the type it casts to
+func (self *Rule) setCast(cast CastFunc, typeName string) {
+	self.Cast = cast
+	self.castType = typeName
+}
+
 func (self *Rule) HasFlag(flag int64) bool {
 	return self.Flags&flag != 0
 }
@@ -93,30 +109,61 @@ func (self *Rule) GenerateUsage() string {
 }
 
 func (self *Rule) GenerateHelp() (string, string) {
-	var parens []string
-	paren := ""
+	if self.HasFlag(IsConfig | IsConfigGroup) {
+		return self.generateConfigHelp()
+	}
 
-	if !self.HasFlag(IsCommand) {
-		if self.Default != nil {
-			parens = append(parens, fmt.Sprintf("Default=%s", *self.Default))
-		}
-		if len(self.EnvVars) != 0 {
-			envs := strings.Join(self.EnvVars, ",")
-			parens = append(parens, fmt.Sprintf("Env=%s", envs))
-		}
-		if len(parens) != 0 {
-			paren = fmt.Sprintf(" (%s)", strings.Join(parens, ", "))
-		}
+	paren := ""
+	if parens := self.helpParens(); parens != "" {
+		paren = " " + parens
 	}
 
 	if self.HasFlag(IsArgument) {
 		return ("  " + self.Name), self.RuleDesc
 	}
+	// Commands list their name first followed by any aliases; IE: 'list, ls'
+	if self.HasFlag(IsCommand) {
+		return ("  " + strings.Join(self.Aliases, ", ")), self.RuleDesc
+	}
 	// TODO: This sort should happen when we validate rules
 	sort.Sort(sort.Reverse(sort.StringSlice(self.Aliases)))
 	return ("  " + strings.Join(self.Aliases, ", ")), (self.RuleDesc + paren)
 }
 
+// Config keys list their group, default, environment variables and backend key
+func (self *Rule) generateConfigHelp() (string, string) {
+	name := self.Name
+	if self.HasFlag(IsConfigGroup) {
+		name = self.Group + ".*"
+	}
+	return ("  " + name), strings.TrimSpace(fmt.Sprintf("%s %s", self.RuleDesc, self.helpParens()))
+}
+
+// Returns the details listed in parens after the help message of the rule. IE: '(Default=1, Env=APP_ENV)'
+func (self *Rule) helpParens() string {
+	var parens []string
+
+	if self.HasFlag(IsCommand) {
+		return ""
+	}
+	if self.HasFlag(IsConfig) && self.Group != DefaultOptionGroup {
+		parens = append(parens, fmt.Sprintf("Group=%s", self.Group))
+	}
+	if self.Default != nil {
+		parens = append(parens, fmt.Sprintf("Default=%s", *self.Default))
+	}
+	if envs := self.envVars(); len(envs) != 0 {
+		parens = append(parens, fmt.Sprintf("Env=%s", strings.Join(envs, ",")))
+	}
+	if self.HasFlag(IsConfig | IsConfigGroup) {
+		parens = append(parens, fmt.Sprintf("Key=%s", self.BackendKey("")))
+	}
+	if len(parens) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(%s)", strings.Join(parens, ", "))
+}
+
 func (self *Rule) MatchesAlias(args []string, idx *int) (bool, string) {
 	for _, alias := range self.Aliases {
 		if args[*idx] == alias {
@@ -238,19 +285,43 @@ func (self *Rule) ComputedValue(values *Options) (interface{}, error) {
 }
 
 func (self *Rule) GetEnvValue() (interface{}, error) {
-	if self.EnvVars == nil {
+	envVars := self.envVars()
+	if envVars == nil {
 		return nil, nil
 	}
 
-	for _, varName := range self.EnvVars {
-		//if value, ok := os.LookupEnv(varName); ok {
-		if value := os.Getenv(varName); value != "" {
+	lookupEnv := self.lookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	// A variable that is set but empty is a value, which allows the user to override a default
+	for _, varName := range envVars {
+		if value, ok := lookupEnv(varName); ok {
 			return self.Cast(varName, self.Value, value)
 		}
 	}
 	return nil, nil
 }
 
+// Returns the environment variables the rule reads. If no variables were assigned via Env()
+// and the parser was created with AutoEnv() the variable is derived from the rule name
+func (self *Rule) envVars() []string {
+	if len(self.EnvVars) == 0 && self.autoEnv {
+		return []string{self.autoEnvName()}
+	}
+	return self.EnvVars
+}
+
+// Returns a variable name derived from the env prefix, group and name of the rule. IE: 'MYCLI_DATABASE_HOST'
+func (self *Rule) autoEnvName() string {
+	name := self.Name
+	if self.Group != DefaultOptionGroup {
+		name = self.Group + "_" + name
+	}
+	return strings.ToUpper(regexNonWord.ReplaceAllString(self.EnvPrefix+name, "_"))
+}
+
 func (self *Rule) BackendKey(rootPath string) string {
 	// Do this so users are not surprised root isn't prefixed with "/"
 	rootPath = "/" + strings.TrimPrefix(rootPath, "/")
@@ -287,3 +358,71 @@ func (self Rules) Less(left, right int) bool {
 func (self Rules) Swap(left, right int) {
 	self[left], self[right] = self[right], self[left]
 }
+
+// Returns the indent needed to align the help messages of all the rules
+func (self Rules) helpIndent() int {
+	maxLen := 0
+	for _, rule := range self {
+		flags, _ := rule.GenerateHelp()
+		if DisplayWidth(flags) > maxLen {
+			maxLen = DisplayWidth(flags)
+		}
+	}
+	return maxLen + 3
+}
+
+// Returns the help indent ignoring flags that would exceed the max indent given
+func (self Rules) helpIndentWithin(max int) int {
+	indent := longFlagIndent
+	for _, rule := range self {
+		flags, _ := rule.GenerateHelp()
+		if width := DisplayWidth(flags) + 3; width <= max && width > indent {
+			indent = width
+		}
+	}
+	return indent
+}
+
+// Returns the categories assigned to the rules in the order they were declared
+func (self Rules) categories() []string {
+	var results []string
+	for _, rule := range self {
+		if rule.Category != "" && !containsString(rule.Category, results) {
+			results = append(results, rule.Category)
+		}
+	}
+	return results
+}
+
+// Returns the groups assigned to the rules in the order they were declared
+func (self Rules) groups() []string {
+	var results []string
+	for _, rule := range self {
+		if !containsString(rule.Group, results) {
+			results = append(results, rule.Group)
+		}
+	}
+	return results
+}
+
+// Returns only the rules assigned to the group provided
+func (self Rules) inGroup(group string) Rules {
+	var results Rules
+	for _, rule := range self {
+		if rule.Group == group {
+			results = append(results, rule)
+		}
+	}
+	return results
+}
+
+// Returns only the rules assigned to the category provided
+func (self Rules) inCategory(category string) Rules {
+	var results Rules
+	for _, rule := range self {
+		if rule.Category == category {
+			results = append(results, rule)
+		}
+	}
+	return results
+}
@@ -0,0 +1,232 @@
+package args
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// A serialisable description of a parser and its rules, see ArgParser.Schema()
+type Schema struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Rules       []RuleSchema `json:"rules"`
+	// Sub commands defined with AddCommands()
+	Commands []*Schema `json:"commands,omitempty"`
+}
+
+// A serialisable description of a rule
+type RuleSchema struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+	// One of 'option', 'argument', 'config', 'config-group' or 'command'
+	Kind string `json:"kind"`
+	// One of 'string', 'int', 'bool', 'list' or 'map'
+	Type       string   `json:"type"`
+	Default    *string  `json:"default,omitempty"`
+	EnvVars    []string `json:"env,omitempty"`
+	Choices    []string `json:"choices,omitempty"`
+	Group      string   `json:"group,omitempty"`
+	BackendKey string   `json:"backendKey,omitempty"`
+	Required   bool     `json:"required"`
+	Help       string   `json:"help,omitempty"`
+}
+
+// Returns a description of the rules of the parser and the sub commands defined with
+// AddCommands() which can be serialised for use by external tools. Options added by
+// the parser such as '--help' are not included.
+//
+//	data, err := json.MarshalIndent(parser.Schema(), "", "  ")
+func (self *ArgParser) Schema() *Schema {
+	schema := &Schema{
+		Name:        self.Name,
+		Description: self.Description,
+		Rules:       []RuleSchema{},
+	}
+
+	for _, rule := range self.schemaRules(IsCommand | IsArgument | IsOption | IsConfig | IsConfigGroup) {
+		ruleSchema := RuleSchema{
+			Name:     rule.Name,
+			Aliases:  rule.Aliases,
+			Kind:     rule.kindName(),
+			Type:     rule.typeName(),
+			Default:  rule.Default,
+			EnvVars:  rule.envVars(),
+			Choices:  rule.Choices,
+			Group:    rule.Group,
+			Required: rule.HasFlag(IsRequired),
+			Help:     rule.RuleDesc,
+		}
+		if rule.HasFlag(IsCommand) {
+			// Commands are named '!cmd-<name>' internally
+			ruleSchema.Name = rule.Aliases[0]
+			ruleSchema.Type = ""
+		} else {
+			ruleSchema.BackendKey = rule.BackendKey("")
+		}
+		schema.Rules = append(schema.Rules, ruleSchema)
+
+		if parser := self.commandDefParser(rule); parser != nil {
+			schema.Commands = append(schema.Commands, parser.Schema())
+		}
+	}
+	return schema
+}
+
+// Returns a JSON Schema (draft-07) document describing a config file for the parser. Options and
+// config keys in the default group are properties of the document, other groups are nested objects
+// and config groups are objects of string values.
+//
+//	schema, err := parser.JSONSchema()
+//	ioutil.WriteFile("config.schema.json", schema, 0644)
+func (self *ArgParser) JSONSchema() ([]byte, error) {
+	root := newJSONObject()
+	root["$schema"] = "http://json-schema.org/draft-07/schema#"
+	if self.Name != "" {
+		root["title"] = self.Name
+	}
+	if self.Description != "" {
+		root["description"] = self.Description
+	}
+
+	for _, rule := range self.schemaRules(IsOption | IsConfig | IsConfigGroup) {
+		parent := root
+		if rule.HasFlag(IsConfigGroup) {
+			properties := root["properties"].(map[string]interface{})
+			group, ok := properties[rule.Group].(map[string]interface{})
+			if !ok {
+				properties[rule.Group] = rule.jsonSchema()
+				continue
+			}
+			// Keep the properties of options in a group by the same name
+			for key, value := range rule.jsonSchema() {
+				group[key] = value
+			}
+			continue
+		}
+		if rule.Group != DefaultOptionGroup {
+			parent = jsonGroup(root, rule.Group)
+		}
+		// A default or environment variable satisfies a required rule
+		required := rule.HasFlag(IsRequired) && rule.Default == nil && len(rule.envVars()) == 0
+		addJSONProperty(parent, rule.Name, rule.jsonSchema(), required)
+	}
+
+	result, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "while marshalling json schema")
+	}
+	return result, nil
+}
+
+// Returns the rules with the given flags excluding options added by the parser and rules
+// inherited from a parent parser
+func (self *ArgParser) schemaRules(flags int64) Rules {
+	var results Rules
+	for _, rule := range self.rules {
+		if rule.HasFlag(flags) && !self.isInherited(rule) && !self.isBuiltin(rule) {
+			results = append(results, rule)
+		}
+	}
+	return results
+}
+
+// Returns true if the rule is an option the parser added. IE: '--help' or '--version'
+func (self *ArgParser) isBuiltin(rule *Rule) bool {
+	switch rule.Name {
+	case "help":
+		return self.helpAdded
+	case "help-config":
+		return self.helpConfigAdded
+	case "help-env":
+		return self.helpEnvAdded
+	case "version":
+		return self.versionAdded
+	case "no-color":
+		return self.colorAdded
+	}
+	return false
+}
+
+// Returns the kind of the rule as reported by Schema()
+func (self *Rule) kindName() string {
+	switch {
+	case self.HasFlag(IsCommand):
+		return "command"
+	case self.HasFlag(IsArgument):
+		return "argument"
+	case self.HasFlag(IsConfigGroup):
+		return "config-group"
+	case self.HasFlag(IsConfig):
+		return "config"
+	}
+	return "option"
+}
+
+// Returns the JSON Schema describing the value of the rule
+func (self *Rule) jsonSchema() map[string]interface{} {
+	result := make(map[string]interface{})
+	switch self.typeName() {
+	case "int":
+		result["type"] = "integer"
+	case "bool":
+		result["type"] = "boolean"
+	case "list":
+		result["type"] = "array"
+		result["items"] = map[string]interface{}{"type": "string"}
+	case "map":
+		result["type"] = "object"
+		result["additionalProperties"] = map[string]interface{}{"type": "string"}
+	default:
+		result["type"] = "string"
+	}
+
+	// Config groups hold any number of keys with string values
+	if self.HasFlag(IsConfigGroup) {
+		result["type"] = "object"
+		result["additionalProperties"] = map[string]interface{}{"type": "string"}
+	}
+	if self.RuleDesc != "" {
+		result["description"] = self.RuleDesc
+	}
+	if len(self.Choices) != 0 {
+		result["enum"] = self.Choices
+	}
+	// Cast the default so it has the same type as the value
+	if self.Default != nil {
+		if value, err := self.Cast(self.Name, nil, *self.Default); err == nil {
+			result["default"] = value
+		}
+	}
+	return result
+}
+
+func newJSONObject() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": make(map[string]interface{}),
+	}
+}
+
+// Returns the object describing the group, creating it if this is the first rule in the group
+func jsonGroup(root map[string]interface{}, name string) map[string]interface{} {
+	properties := root["properties"].(map[string]interface{})
+	group, ok := properties[name].(map[string]interface{})
+	if !ok {
+		group = newJSONObject()
+		properties[name] = group
+	}
+	// The object of a config group by the same name has no properties
+	if _, ok := group["properties"]; !ok {
+		group["properties"] = make(map[string]interface{})
+	}
+	return group
+}
+
+func addJSONProperty(object map[string]interface{}, name string, schema map[string]interface{}, required bool) {
+	object["properties"].(map[string]interface{})[name] = schema
+	if required {
+		list, _ := object["required"].([]string)
+		object["required"] = append(list, name)
+	}
+}
@@ -0,0 +1,124 @@
+package args_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+)
+
+var _ = Describe("Schema", func() {
+	var parser *args.ArgParser
+
+	BeforeEach(func() {
+		parser = args.NewParser(args.Name("my-cli"), args.Desc("Manage volumes"))
+		parser.AddOption("--endpoint").Alias("-e").Env("API_ENDPOINT").Default("http://localhost").
+			Help("The api endpoint")
+		parser.AddOption("--retries").IsInt().Default("3")
+		parser.AddOption("--format").Choices([]string{"json", "text"}).Default("text")
+		parser.AddConfig("user").InGroup("database").Required().Help("Database user")
+		parser.AddConfig("tags").InGroup("database").IsStringSlice().Default("a,b")
+		parser.AddConfigGroup("endpoints").Help("Endpoints by service name")
+		parser.AddCommands(args.Command{
+			Name: "volume",
+			Help: "Manage volumes",
+			Options: func(subParser *args.ArgParser) {
+				subParser.AddArgument("name").Required().Help("The name of the volume")
+			},
+		})
+	})
+
+	Describe("ArgParser.Schema()", func() {
+		It("Should describe every rule", func() {
+			// Options added by the parser should not be included
+			_, err := parser.Parse(&[]string{})
+			Expect(err).To(MatchError("config 'user' is required"))
+
+			schema := parser.Schema()
+			Expect(schema.Name).To(Equal("my-cli"))
+			Expect(len(schema.Rules)).To(Equal(7))
+
+			endpoint := schema.Rules[0]
+			Expect(endpoint.Name).To(Equal("endpoint"))
+			Expect(endpoint.Kind).To(Equal("option"))
+			Expect(endpoint.Type).To(Equal("string"))
+			Expect(*endpoint.Default).To(Equal("http://localhost"))
+			Expect(endpoint.EnvVars).To(Equal([]string{"API_ENDPOINT"}))
+			Expect(endpoint.BackendKey).To(Equal("/endpoint"))
+
+			Expect(schema.Rules[3].Kind).To(Equal("config"))
+			Expect(schema.Rules[3].Required).To(Equal(true))
+			Expect(schema.Rules[3].BackendKey).To(Equal("/database/user"))
+			Expect(schema.Rules[6].Name).To(Equal("volume"))
+			Expect(schema.Rules[6].Kind).To(Equal("command"))
+
+			Expect(len(schema.Commands)).To(Equal(1))
+			Expect(schema.Commands[0].Name).To(Equal("my-cli volume"))
+			Expect(schema.Commands[0].Rules[0].Kind).To(Equal("argument"))
+
+			_, err = json.Marshal(schema)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Describe("ArgParser.JSONSchema()", func() {
+		It("Should describe the config file", func() {
+			data, err := parser.JSONSchema()
+			Expect(err).To(BeNil())
+			Expect(data).To(MatchJSON(`{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"title": "my-cli",
+				"description": "Manage volumes",
+				"type": "object",
+				"properties": {
+					"endpoint": {"type": "string", "description": "The api endpoint", "default": "http://localhost"},
+					"retries": {"type": "integer", "default": 3},
+					"format": {"type": "string", "enum": ["json", "text"], "default": "text"},
+					"database": {
+						"type": "object",
+						"properties": {
+							"user": {"type": "string", "description": "Database user"},
+							"tags": {"type": "array", "items": {"type": "string"}, "default": ["a", "b"]}
+						},
+						"required": ["user"]
+					},
+					"endpoints": {
+						"type": "object",
+						"additionalProperties": {"type": "string"},
+						"description": "Endpoints by service name"
+					}
+				}
+			}`))
+		})
+		It("Should merge a config group with the options in a group of the same name", func() {
+			expected := `{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"type": "object",
+				"properties": {
+					"endpoints": {
+						"type": "object",
+						"properties": {
+							"default": {"type": "string"}
+						},
+						"additionalProperties": {"type": "string"}
+					}
+				}
+			}`
+
+			parser := args.NewParser()
+			parser.AddConfigGroup("endpoints")
+			parser.AddConfig("default").InGroup("endpoints")
+			data, err := parser.JSONSchema()
+			Expect(err).To(BeNil())
+			Expect(data).To(MatchJSON(expected))
+
+			parser = args.NewParser()
+			parser.AddConfig("default").InGroup("endpoints")
+			parser.AddConfigGroup("endpoints")
+			data, err = parser.JSONSchema()
+			Expect(err).To(BeNil())
+			Expect(data).To(MatchJSON(expected))
+		})
+	})
+})
@@ -0,0 +1,90 @@
+package args
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// Set how long ParseAndRunContext() waits for a command to return after the context
+// is cancelled by SIGINT or SIGTERM before forcing the process to exit. A grace of zero
+// waits until the user sends a second signal.
+func GracePeriod(grace time.Duration) ParseModifier {
+	return func(parser *ArgParser) {
+		parser.GracePeriod = grace
+	}
+}
+
+// Parse the command line and run the chosen command with a context that is cancelled when
+// the process receives SIGINT or SIGTERM. Commands retrieve the context via Context()
+//
+//	parser.AddCommand("watch", func(subParser *args.ArgParser, data interface{}) (int, error) {
+//		ctx := subParser.Context()
+//		for {
+//			select {
+//			case <-ctx.Done():
+//				return 0, nil
+//			case event := <-events:
+//				fmt.Println(event)
+//			}
+//		}
+//	})
+//	retCode, err := parser.ParseAndRunContext(context.Background(), nil, nil)
+//
+// If the command does not return within the GracePeriod after the first signal, or the
+// user sends a second signal the process exits immediately.
+func (self *ArgParser) ParseAndRunContext(ctx context.Context, args *[]string, data interface{}) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := make(chan struct{})
+	defer close(done)
+	go self.handleSignals(signals, cancel, done)
+
+	// Restore the previous context on return so the parser doesn't keep a cancelled context
+	defer func(prev context.Context) { self.ctx = prev }(self.ctx)
+	self.ctx = ctx
+	return self.ParseAndRun(args, data)
+}
+
+func (self *ArgParser) handleSignals(signals <-chan os.Signal, cancel context.CancelFunc, done <-chan struct{}) {
+	var sig os.Signal
+	select {
+	case sig = <-signals:
+		self.info("Received '%s'; cancelling command", sig)
+		cancel()
+	case <-done:
+		return
+	}
+
+	// A nil channel never fires, so without a grace period we wait for a second signal
+	var deadline <-chan time.Time
+	if self.GracePeriod > 0 {
+		deadline = time.After(self.GracePeriod)
+	}
+
+	select {
+	case sig = <-signals:
+		self.info("Received '%s' while shutting down; exiting", sig)
+	case <-deadline:
+		self.info("Command did not exit within %s; exiting", self.GracePeriod)
+	case <-done:
+		return
+	}
+	self.exit(signalExitCode(sig))
+}
+
+// Returns the conventional shell exit code for a process terminated by a signal
+func signalExitCode(sig os.Signal) int {
+	if num, ok := sig.(syscall.Signal); ok {
+		return 128 + int(num)
+	}
+	return 1
+}
@@ -0,0 +1,31 @@
+package args_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("ArgParser", func() {
+	Describe("ArgParser.ParseAndRunContext()", func() {
+		It("Should pass the context to the command", func() {
+			type key string
+			parser := args.NewParser()
+			parser.AddCommand("show", func(subParser *args.ArgParser, data interface{}) (int, error) {
+				Expect(subParser.Context().Value(key("user"))).To(Equal("thrawn01"))
+				return 0, nil
+			})
+
+			ctx := context.WithValue(context.Background(), key("user"), "thrawn01")
+			cmdLine := []string{"show"}
+			retCode, err := parser.ParseAndRunContext(ctx, &cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(0))
+		})
+		It("Should return context.Background() if not run with a context", func() {
+			parser := args.NewParser()
+			Expect(parser.Context()).To(Equal(context.Background()))
+		})
+	})
+})
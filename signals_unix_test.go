@@ -0,0 +1,101 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package args_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+	"golang.org/x/net/context"
+)
+
+// Ginkgo aborts the suite on SIGINT, so the signal specs re-run the test binary
+// with ARGS_SIGNAL_SCENARIO set and inspect the output and exit code of the child
+func init() {
+	if scenario := os.Getenv("ARGS_SIGNAL_SCENARIO"); scenario != "" {
+		os.Exit(runSignalScenario(scenario))
+	}
+}
+
+func runSignalScenario(scenario string) int {
+	interrupt := func() {
+		if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+			fmt.Printf("kill failed: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	parser := args.NewParser()
+	parser.Exit = func(code int) {
+		fmt.Printf("exit %d\n", code)
+		os.Exit(code)
+	}
+	parser.AddCommand("watch", func(subParser *args.ArgParser, data interface{}) (int, error) {
+		interrupt()
+		<-subParser.Context().Done()
+		fmt.Println("cancelled")
+		switch scenario {
+		case "grace":
+			// Ignore the cancelled context until the parser gives up on us
+			time.Sleep(10 * time.Second)
+		case "second":
+			interrupt()
+			time.Sleep(10 * time.Second)
+		}
+		return 0, nil
+	})
+	if scenario == "grace" {
+		args.GracePeriod(50 * time.Millisecond)(parser)
+	} else {
+		args.GracePeriod(time.Hour)(parser)
+	}
+
+	cmdLine := []string{"watch"}
+	retCode, err := parser.ParseAndRunContext(context.Background(), &cmdLine, nil)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	if parser.Context() == context.Background() {
+		fmt.Println("context restored")
+	}
+	return retCode
+}
+
+var _ = Describe("ArgParser", func() {
+	Describe("ArgParser.ParseAndRunContext() signals", func() {
+		runScenario := func(scenario string) (string, int) {
+			cmd := exec.Command(os.Args[0])
+			cmd.Env = append(os.Environ(), "ARGS_SIGNAL_SCENARIO="+scenario)
+			output, err := cmd.Output()
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				return string(output), exitErr.ExitCode()
+			}
+			Expect(err).To(BeNil())
+			return string(output), 0
+		}
+
+		It("Should cancel the context when the process receives SIGINT", func() {
+			output, exitCode := runScenario("cancel")
+			Expect(output).To(Equal("cancelled\ncontext restored\n"))
+			Expect(exitCode).To(Equal(0))
+		})
+		It("Should exit if the command does not return within the grace period", func() {
+			output, exitCode := runScenario("grace")
+			Expect(output).To(Equal("cancelled\nexit 130\n"))
+			Expect(exitCode).To(Equal(130))
+		})
+		It("Should exit immediately if the process receives a second signal", func() {
+			output, exitCode := runScenario("second")
+			Expect(output).To(Equal("cancelled\nexit 130\n"))
+			Expect(exitCode).To(Equal(130))
+		})
+	})
+})
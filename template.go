@@ -0,0 +1,197 @@
+package args
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// The data model available to templates set with SetHelpTemplate() and SetUsageTemplate()
+type HelpData struct {
+	// The name of the program followed by the command path. IE: 'my-cli volume create'
+	Name        string
+	Description string
+	// The usage line, rendered with the usage template if one was set
+	Usage string
+	// Visible commands, arguments, options and config keys in the order they were declared
+	Commands  []HelpRule
+	Arguments []HelpRule
+	Options   []HelpRule
+	// Persistent options inherited from parent parsers
+	GlobalOptions []HelpRule
+	Config        []HelpRule
+	// Every environment variable read by a visible rule
+	EnvVars []HelpEnv
+	// Visible options and config keys by group in display order, the default group is named ""
+	Groups []HelpGroup
+	// Examples of the program or command, CmdLine includes the program name and command path
+	Examples []Example
+	// The width of the flags column used by the default help message
+	Indent int
+	// The column help messages wrap at
+	WordWrap int
+}
+
+// A rule as seen by help templates
+type HelpRule struct {
+	// The name of the rule. IE: 'endpoint'
+	Name string
+	// The aliases of an option or command, the name of an argument. IE: '--endpoint, -e'
+	Flags    string
+	Aliases  []string
+	Help     string
+	Default  string
+	EnvVars  []string
+	Group    string
+	Category string
+	Required bool
+	// The help message followed by the default and environment variables
+	Message string
+}
+
+type HelpEnv struct {
+	Name string
+	Rule HelpRule
+}
+
+type HelpGroup struct {
+	Name string
+	// The description given by GroupDesc()
+	Desc  string
+	Rules []HelpRule
+}
+
+// Functions available to help and usage templates
+//
+//	wrap <text> <indent> <width>   Word wrap the text, indenting new lines
+//	pad <text> <width>             Pad the text with spaces to the width given
+//	join <list> <separator>        Join a list of strings
+//	indent <text> <width>          Indent every line of the text
+var templateFuncs = template.FuncMap{
+	"wrap": WordWrap,
+	"pad": func(text string, width int) string {
+		if pad := width - DisplayWidth(text); pad > 0 {
+			return text + strings.Repeat(" ", pad)
+		}
+		return text
+	},
+	"join": func(items []string, sep string) string {
+		return strings.Join(items, sep)
+	},
+	"indent": func(text string, width int) string {
+		spaces := strings.Repeat(" ", width)
+		return spaces + strings.Replace(text, "\n", "\n"+spaces, -1)
+	},
+}
+
+// Replace the help message generated by GenerateHelp() with a text/template executed
+// with HelpData. Sub parsers created by SubParser() inherit the template.
+//
+//	err := parser.SetHelpTemplate(`{{.Usage}}
+//	{{range .Options}}{{pad .Flags 20}}{{.Help}}
+//	{{end}}`)
+func (self *ArgParser) SetHelpTemplate(text string) error {
+	tmpl, err := template.New("help").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return errors.Wrap(err, "while parsing help template")
+	}
+	self.helpTemplate = tmpl
+	return nil
+}
+
+// Replace the 'Usage: ' line of the help message and usage errors with a text/template
+// executed with HelpData. The Usage field of HelpData is not available to this template.
+//
+//	err := parser.SetUsageTemplate(`usage: {{.Name}} [flags]{{range .Arguments}} {{.Name}}{{end}}`)
+func (self *ArgParser) SetUsageTemplate(text string) error {
+	tmpl, err := template.New("usage").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return errors.Wrap(err, "while parsing usage template")
+	}
+	self.usageTemplate = tmpl
+	return nil
+}
+
+// Returns the data model used to render help and usage templates
+func (self *ArgParser) HelpData() *HelpData {
+	data := &HelpData{
+		Name:          self.Name,
+		Description:   self.Description,
+		Commands:      newHelpRules(self.helpRules(IsCommand)),
+		Arguments:     newHelpRules(self.helpRules(IsArgument)),
+		Options:       newHelpRules(self.helpRules(IsOption)),
+		GlobalOptions: newHelpRules(self.globalRules()),
+		Config:        newHelpRules(self.helpRules(IsConfig | IsConfigGroup)),
+		WordWrap:      self.wrapLen(),
+	}
+
+	for _, example := range self.examples {
+		data.Examples = append(data.Examples, Example{CmdLine: self.exampleCmdLine(example), Desc: example.Desc})
+	}
+
+	rules := append(self.helpRules(IsOption), self.helpRules(IsConfig|IsConfigGroup)...)
+	for _, group := range self.orderGroups(rules.groups()) {
+		data.Groups = append(data.Groups, HelpGroup{
+			Name:  group,
+			Desc:  self.groupDesc[group],
+			Rules: newHelpRules(rules.inGroup(group)),
+		})
+	}
+
+	for _, rules := range [][]HelpRule{data.Arguments, data.Options, data.GlobalOptions, data.Config} {
+		for _, rule := range rules {
+			for _, env := range rule.EnvVars {
+				data.EnvVars = append(data.EnvVars, HelpEnv{Name: env, Rule: rule})
+			}
+		}
+	}
+
+	for _, rules := range [][]HelpRule{data.Commands, data.Arguments, data.Options, data.GlobalOptions} {
+		for _, rule := range rules {
+			// Match the column width the default help message uses
+			if len(rule.Flags)+5 > data.Indent {
+				data.Indent = len(rule.Flags) + 5
+			}
+		}
+	}
+
+	// The usage template is rendered before the Usage field is set
+	if self.usageTemplate != nil {
+		data.Usage = self.renderTemplate(self.usageTemplate, data)
+	} else {
+		data.Usage = self.usageLine()
+	}
+	return data
+}
+
+func newHelpRules(rules Rules) []HelpRule {
+	var results []HelpRule
+	for _, rule := range rules {
+		flags, message := rule.GenerateHelp()
+		results = append(results, HelpRule{
+			Name:     rule.Name,
+			Flags:    strings.TrimSpace(flags),
+			Aliases:  rule.Aliases,
+			Help:     rule.RuleDesc,
+			Default:  rule.defaultString(),
+			EnvVars:  rule.envVars(),
+			Group:    rule.Group,
+			Category: rule.Category,
+			Required: rule.HasFlag(IsRequired),
+			Message:  message,
+		})
+	}
+	return results
+}
+
+// Execute the template, returns a description of the error instead if the template failed
+func (self *ArgParser) renderTemplate(tmpl *template.Template, data *HelpData) string {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Sprintf("error executing %s template: %s", tmpl.Name(), err)
+	}
+	return buf.String()
+}
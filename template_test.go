@@ -0,0 +1,55 @@
+package args_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+)
+
+var _ = Describe("Templates", func() {
+	var parser *args.ArgParser
+
+	BeforeEach(func() {
+		parser = args.NewParser(args.Name("my-cli"), args.Desc("Manage volumes"), args.NoHelp())
+		parser.AddOption("--endpoint").Alias("-e").Env("API_ENDPOINT").Help("The api endpoint")
+		parser.AddOption("--debug").IsTrue().Persistent().Help("Enable debug")
+		parser.AddConfig("token").InGroup("auth").Help("The api token")
+		parser.AddArgument("name").Required().Help("The name of the volume")
+	})
+
+	Describe("ArgParser.SetHelpTemplate()", func() {
+		It("Should render the help message with the template", func() {
+			err := parser.SetHelpTemplate(`{{.Usage}}
+{{range .Options}}{{pad .Flags 18}}{{.Help}}
+{{end}}{{range .EnvVars}}{{.Name}} sets {{.Rule.Name}}
+{{end}}{{range .Groups}}[{{.Name}}]{{range .Rules}} {{.Name}}{{end}}
+{{end}}`)
+			Expect(err).To(BeNil())
+			Expect(parser.GenerateHelp()).To(Equal("Usage: my-cli [OPTIONS]  <name>\n" +
+				"-e, --endpoint    The api endpoint\n" +
+				"--debug           Enable debug\n" +
+				"API_ENDPOINT sets endpoint\n" +
+				"[] endpoint debug\n" +
+				"[auth] token\n"))
+		})
+		It("Should be inherited by sub parsers", func() {
+			Expect(parser.SetHelpTemplate(`{{.Name}}:{{range .GlobalOptions}} {{.Flags}}{{end}}`)).To(Succeed())
+			subParser := parser.SubParser()
+			subParser.Name = "my-cli volume"
+			Expect(subParser.GenerateHelp()).To(Equal("my-cli volume: --debug"))
+		})
+		It("Should return an error if the template is invalid", func() {
+			err := parser.SetHelpTemplate(`{{.Usage`)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("while parsing help template"))
+		})
+	})
+
+	Describe("ArgParser.SetUsageTemplate()", func() {
+		It("Should render the usage line with the template", func() {
+			err := parser.SetUsageTemplate(`usage: {{.Name}} [flags]{{range .Arguments}} {{.Name}}{{end}}`)
+			Expect(err).To(BeNil())
+			Expect(parser.GenerateHelp()).To(HavePrefix("usage: my-cli [flags] name\n\nManage volumes\n"))
+		})
+	})
+})
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package args
+
+import "os"
+
+// Terminal detection is not supported on this platform, the 'COLUMNS'
+// environment variable or DefaultWrapLen is used instead
+func terminalWidth(file *os.File) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,25 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package args
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+type winSize struct {
+	rows, cols, xPixel, yPixel uint16
+}
+
+// Returns the width of the terminal if the file is attached to one
+func terminalWidth(file *os.File) (int, bool) {
+	var size winSize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(),
+		uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)))
+	if errno != 0 {
+		return 0, false
+	}
+	return int(size.cols), true
+}
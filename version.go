@@ -0,0 +1,177 @@
+package args
+
+import (
+	"bytes"
+	"fmt"
+	"runtime/debug"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The template used to print the version when SetVersionTemplate() was not called
+//
+//	my-cli 1.2.0 (8c1e2f4d0c7a, modified) go1.22.1
+const DefaultVersionTemplate = `{{.Name}} {{.Version}}{{if .Revision}} ({{.Revision}}{{if .Dirty}}, modified{{end}}){{end}}` +
+	`{{if .GoVersion}} {{.GoVersion}}{{end}}`
+
+// The data model available to the version template
+type VersionInfo struct {
+	// The name of the program
+	Name string
+	// The version given to Version() or the module version from the build info
+	Version string
+	// The abbreviated VCS revision the binary was built from, if known
+	Revision string
+	// True if the working tree had uncommitted changes when the binary was built
+	Dirty bool
+	// The version of Go the binary was built with
+	GoVersion string
+}
+
+// Adds a '--version' option which prints the version given and exits. If the parser has
+// commands a 'version' command is also added. The VCS revision and Go version are read from
+// the build info of the binary
+//
+//	parser := args.NewParser(args.Name("my-cli"), args.Version("1.2.0"))
+func Version(version string) ParseModifier {
+	return func(parser *ArgParser) {
+		parser.version = readBuildInfo()
+		parser.version.Version = version
+	}
+}
+
+// Replaced by tests to simulate the build info of the binary
+var readBuildInfoFunc = debug.ReadBuildInfo
+
+// Like Version() but the version is the module version recorded in the build info of the
+// binary, which is set when the binary is installed with `go install module@version`. Binaries
+// built with `go build` have no module version, for those the version is a pseudo version
+// derived from the VCS revision and commit time if known, else the default given
+//
+//	parser := args.NewParser(args.Name("my-cli"), args.VersionFromBuildInfo("dev"))
+func VersionFromBuildInfo(defaultVersion string) ParseModifier {
+	return func(parser *ArgParser) {
+		parser.version = readBuildInfo()
+		parser.version.Version = buildVersion(defaultVersion)
+	}
+}
+
+// Returns the module version from the build info, a pseudo version if the module version
+// is unknown. IE: 'v0.0.0-20240102150405-8c1e2f4d0c7a' or the default if neither is known
+func buildVersion(defaultVersion string) string {
+	build, ok := readBuildInfoFunc()
+	if !ok {
+		return defaultVersion
+	}
+	if build.Main.Version != "" && build.Main.Version != "(devel)" {
+		return build.Main.Version
+	}
+
+	var revision, commitTime string
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.time":
+			commitTime = setting.Value
+		}
+	}
+	created, err := time.Parse(time.RFC3339, commitTime)
+	if revision == "" || err != nil {
+		return defaultVersion
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	return fmt.Sprintf("v0.0.0-%s-%s", created.UTC().Format("20060102150405"), revision)
+}
+
+// Returns the revision, dirty flag and Go version from the build info of the binary
+func readBuildInfo() *VersionInfo {
+	info := &VersionInfo{}
+	build, ok := readBuildInfoFunc()
+	if !ok {
+		return info
+	}
+	info.GoVersion = build.GoVersion
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+			if len(info.Revision) > 12 {
+				info.Revision = info.Revision[:12]
+			}
+		case "vcs.modified":
+			info.Dirty = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+// Replace the DefaultVersionTemplate with a text/template executed with VersionInfo
+//
+//	err := parser.SetVersionTemplate(`{{.Name}} version {{.Version}}`)
+func (self *ArgParser) SetVersionTemplate(text string) error {
+	tmpl, err := template.New("version").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return errors.Wrap(err, "while parsing version template")
+	}
+	self.versionTemplate = tmpl
+	return nil
+}
+
+// Returns the version message printed by --version, an empty string if no version was given
+func (self *ArgParser) GenerateVersion() string {
+	if self.version == nil {
+		return ""
+	}
+	tmpl := self.versionTemplate
+	if tmpl == nil {
+		tmpl = template.Must(template.New("version").Parse(DefaultVersionTemplate))
+	}
+
+	info := *self.version
+	info.Name = self.Name
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, info); err != nil {
+		return fmt.Sprintf("error executing %s template: %s", tmpl.Name(), err)
+	}
+	return buf.String()
+}
+
+// Print the version message to HelpIO
+func (self *ArgParser) PrintVersion() {
+	fmt.Fprintln(self.HelpIO, self.GenerateVersion())
+}
+
+// Add the --version option and the version command if a version was given
+func (self *ArgParser) addVersionOption() {
+	if self.version == nil || self.IsSubParser {
+		return
+	}
+	if self.GetRule("version") == nil {
+		self.AddOption("--version").IsTrue().NoAutoEnv().Help("Display the version and exit")
+		self.versionAdded = true
+	}
+	// Only add the command if the parser has commands and the user didn't define their own
+	if len(self.helpRules(IsCommand)) != 0 && self.matchAlias("version", IsCommand) == nil {
+		self.AddCommand("version", func(subParser *ArgParser, data interface{}) (int, error) {
+			self.PrintVersion()
+			return 0, nil
+		}).Help("Display the version and exit")
+	}
+}
+
+// Returns true if the user asked for the version via --version
+func IsVersionError(err error) bool {
+	_, ok := err.(*VersionError)
+	return ok
+}
+
+type VersionError struct{}
+
+func (e *VersionError) Error() string {
+	return "User asked for the version; Inspect this error with args.IsVersionError(err)"
+}
@@ -0,0 +1,101 @@
+package args_test
+
+import (
+	"bytes"
+	"runtime/debug"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+)
+
+var _ = Describe("Version", func() {
+	var stdout bytes.Buffer
+	var parser *args.ArgParser
+
+	BeforeEach(func() {
+		stdout.Reset()
+		parser = args.NewParser(args.Name("my-cli"), args.Version("1.2.0"))
+		parser.HelpIO = &stdout
+		parser.AddOption("--endpoint").Help("The api endpoint")
+	})
+
+	Describe("args.Version()", func() {
+		It("Should print the version with --version", func() {
+			cmdLine := []string{"--version"}
+			_, err := parser.Parse(&cmdLine)
+			Expect(args.IsVersionError(err)).To(Equal(true))
+
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(0))
+			Expect(stdout.String()).To(HavePrefix("my-cli 1.2.0"))
+		})
+		It("Should add a version command if the parser has commands", func() {
+			parser.AddCommand("status", func(subParser *args.ArgParser, data interface{}) (int, error) {
+				return 0, nil
+			})
+			Expect(parser.SetVersionTemplate("{{.Name}} version {{.Version}}")).To(Succeed())
+
+			cmdLine := []string{"version"}
+			retCode, err := parser.ParseAndRun(&cmdLine, nil)
+			Expect(err).To(BeNil())
+			Expect(retCode).To(Equal(0))
+			Expect(stdout.String()).To(Equal("my-cli version 1.2.0\n"))
+			Expect(parser.GenerateHelp()).To(ContainSubstring("  version   Display the version and exit"))
+		})
+		It("Should not add --version if no version was given", func() {
+			parser := args.NewParser()
+			parser.AddOption("--endpoint")
+			_, err := parser.Parse(&[]string{})
+			Expect(err).To(BeNil())
+			Expect(parser.GetRule("version")).To(BeNil())
+		})
+	})
+	Describe("args.VersionFromBuildInfo()", func() {
+		var build *debug.BuildInfo
+		var restore func()
+
+		BeforeEach(func() {
+			build = &debug.BuildInfo{
+				GoVersion: "go1.22.1",
+				Main:      debug.Module{Path: "github.com/thrawn01/my-cli", Version: "v1.2.0"},
+				Settings: []debug.BuildSetting{
+					{Key: "vcs.revision", Value: "8c1e2f4d0c7a9b3e5f6a7b8c9d0e1f2a3b4c5d6e"},
+					{Key: "vcs.time", Value: "2024-01-02T15:04:05Z"},
+					{Key: "vcs.modified", Value: "true"},
+				},
+			}
+			restore = args.SetReadBuildInfo(func() (*debug.BuildInfo, bool) {
+				return build, build != nil
+			})
+		})
+
+		AfterEach(func() {
+			restore()
+		})
+
+		generateVersion := func() string {
+			parser := args.NewParser(args.Name("my-cli"), args.VersionFromBuildInfo("dev"))
+			return parser.GenerateVersion()
+		}
+
+		It("Should use the module version from the build info", func() {
+			Expect(generateVersion()).To(Equal("my-cli v1.2.0 (8c1e2f4d0c7a, modified) go1.22.1"))
+		})
+		It("Should use a pseudo version if the module version is (devel)", func() {
+			build.Main.Version = "(devel)"
+			Expect(generateVersion()).To(Equal(
+				"my-cli v0.0.0-20240102150405-8c1e2f4d0c7a (8c1e2f4d0c7a, modified) go1.22.1"))
+		})
+		It("Should use the default if the build info has no VCS settings", func() {
+			build.Main.Version = "(devel)"
+			build.Settings = nil
+			Expect(generateVersion()).To(Equal("my-cli dev go1.22.1"))
+		})
+		It("Should use the default if the build info is missing", func() {
+			build = nil
+			Expect(generateVersion()).To(Equal("my-cli dev"))
+		})
+	})
+})
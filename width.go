@@ -0,0 +1,110 @@
+package args
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The width used when the terminal width can not be detected
+const DefaultWrapLen = 80
+
+// The narrowest the description column of the help message is allowed to get
+const minWrapLen = 20
+
+// The indent used for descriptions when every flag in a section is too long to share a line
+const longFlagIndent = 8
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;?]*[ -/]*[@-~]")
+
+// Wide characters (CJK, Hangul, full width forms and emoji) occupy two terminal columns
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F900, 0x1F9FF},
+	{0x20000, 0x2FFFD},
+	{0x30000, 0x3FFFD},
+}
+
+// Returns the number of terminal columns the string occupies, ANSI escape
+// sequences are ignored and wide characters count as two columns
+//
+//	args.DisplayWidth("\x1b[1mhello\x1b[0m") == 5
+//	args.DisplayWidth("日本") == 4
+func DisplayWidth(text string) int {
+	width := 0
+	for _, r := range ansiEscape.ReplaceAllString(text, "") {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// Returns the number of terminal columns the rune occupies
+func runeWidth(r rune) int {
+	if r == 0 || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r) {
+		return 0
+	}
+	for _, wide := range wideRanges {
+		if r >= wide.lo && r <= wide.hi {
+			return 2
+		}
+	}
+	return 1
+}
+
+// Splits the text into printable cells; an ANSI escape sequence is a single cell with no width
+func splitCells(text string) (cells []string, widths []int) {
+	for len(text) != 0 {
+		if loc := ansiEscape.FindStringIndex(text); loc != nil && loc[0] == 0 {
+			cells = append(cells, text[:loc[1]])
+			widths = append(widths, 0)
+			text = text[loc[1]:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(text)
+		cells = append(cells, text[:size])
+		widths = append(widths, runeWidth(r))
+		text = text[size:]
+	}
+	return cells, widths
+}
+
+// Returns the width of the terminal the writer is attached to. If the writer is not a
+// terminal the width is taken from the 'COLUMNS' environment variable, else DefaultWrapLen
+func TerminalWidth(out io.Writer, lookupEnv func(string) (string, bool)) int {
+	if file, ok := out.(*os.File); ok {
+		if width, ok := terminalWidth(file); ok && width > 0 {
+			return width
+		}
+	}
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	if value, ok := lookupEnv("COLUMNS"); ok {
+		if width, err := strconv.Atoi(value); err == nil && width > 0 {
+			return width
+		}
+	}
+	return DefaultWrapLen
+}
+
+// Returns the width help messages are wrapped at; WordWrap if set, else the terminal width
+func (self *ArgParser) wrapLen() int {
+	if self.WordWrap > 0 {
+		return self.WordWrap
+	}
+	return TerminalWidth(self.HelpIO, self.LookupEnv)
+}
@@ -0,0 +1,51 @@
+package args_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/thrawn01/args"
+)
+
+var _ = Describe("Width", func() {
+	Describe("args.DisplayWidth()", func() {
+		It("Should count wide characters as two columns and ignore ANSI escapes", func() {
+			Expect(args.DisplayWidth("hello")).To(Equal(5))
+			Expect(args.DisplayWidth("日本")).To(Equal(4))
+			Expect(args.DisplayWidth("\x1b[1mhello\x1b[0m")).To(Equal(5))
+		})
+	})
+	Describe("args.WordWrap()", func() {
+		It("Should wrap wide characters by display width", func() {
+			msg := args.WordWrap("日本語 日本語 日本語 日本語 日本語 日本語", 0, 24)
+			Expect(msg).To(Equal("日本語 日本語 日本語\n 日本語 日本語 日本語"))
+		})
+		It("Should not count ANSI escapes towards the width", func() {
+			msg := args.WordWrap("\x1b[1mbold\x1b[0m text that is long enough to wrap here", 0, 24)
+			Expect(msg).To(Equal("\x1b[1mbold\x1b[0m text that is long\n enough to wrap here"))
+		})
+		It("Should not panic if the indent exceeds the width", func() {
+			msg := args.WordWrap("a message that would not fit when the indent exceeds the width", 40, 30)
+			Expect(msg).To(HavePrefix("a message that\n"))
+		})
+	})
+	Describe("ArgParser.GenerateHelpSection()", func() {
+		It("Should wrap at the width in COLUMNS and put long flags on their own line", func() {
+			parser := args.NewParser(args.Name("my-cli"), args.NoHelp())
+			parser.LookupEnv = func(key string) (string, bool) {
+				if key == "COLUMNS" {
+					return "40", true
+				}
+				return "", false
+			}
+			parser.AddOption("--endpoint").Alias("-e").Help("The api endpoint to send requests to")
+			parser.AddOption("--a-really-long-option-name-that-squeezes").Help("Squeezes the description column")
+
+			Expect(parser.GenerateHelpSection(args.IsOption)).To(Equal(
+				"  -e, --endpoint   The api endpoint to\n" +
+					"                   send requests to\n" +
+					"  --a-really-long-option-name-that-squeezes\n" +
+					"                   Squeezes the\n" +
+					"                   description column\n"))
+		})
+	})
+})